@@ -0,0 +1,245 @@
+package analysistest_test
+
+import (
+	"fmt"
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/analysistest"
+	"go-test/lib/comm"
+	"go-test/lib/global-non-preemptive"
+	"go-test/lib/uni-non-preemptive"
+	"go-test/lib/uni-non-preemptive-por"
+	"go-test/lib/uni-preemptive"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// analyses are the analyses under test by name.
+var analyses = map[string]analysistest.Analysis{
+	"uni": func(w comm.JobSet, opts comm.AnalysisOptions) *comm.AnalysisResult {
+		return uni_non_preemptive.NewSpace(w, opts).Explore()
+	},
+	"por": func(w comm.JobSet, opts comm.AnalysisOptions) *comm.AnalysisResult {
+		return uni_non_preemptive_por.NewSpace(w, opts).Explore()
+	},
+	"preemptive": func(w comm.JobSet, opts comm.AnalysisOptions) *comm.AnalysisResult {
+		return uni_preemptive.NewSpace(w, opts).Explore()
+	},
+	"global": func(w comm.JobSet, opts comm.AnalysisOptions) *comm.AnalysisResult {
+		return global_non_preemptive.NewSpace(w, opts).Explore()
+	},
+}
+
+// workload is a job set of the examples with the options of its analysis.
+type workload struct {
+	analysis string
+	file     string
+	prec     string
+	tasks    bool
+	cores    uint
+	iip      func(comm.JobSet) comm.IIP
+}
+
+func (w workload) name() string {
+	return w.analysis + " " + w.file + " " + w.prec
+}
+
+// read returns the jobs of w and the options of its analysis.
+func (w workload) read(t *testing.T) (comm.JobSet, comm.AnalysisOptions) {
+	t.Helper()
+	var jobs comm.JobSet
+	if w.tasks {
+		jobs = analysistest.ReadTasks(t, w.file)
+	} else {
+		jobs = analysistest.ReadWorkload(t, w.file, w.prec)
+	}
+	opts := comm.AnalysisOptions{Cores: w.cores, Logger: verbose.New("test")}
+	if w.iip != nil {
+		opts.IIP = w.iip(jobs)
+	}
+	return jobs, opts
+}
+
+// explore runs the analysis of w with the options changed by with.
+func (w workload) explore(t *testing.T, with func(*comm.AnalysisOptions)) *comm.AnalysisResult {
+	t.Helper()
+	jobs, opts := w.read(t)
+	with(&opts)
+	return analyses[w.analysis](jobs, opts)
+}
+
+// workloads are small enough for every analysis to explore them quickly.
+// The preemptive analysis is slow on example4.
+var workloads = []workload{
+	{analysis: "uni", file: "example.csv"},
+	{analysis: "uni", file: "example3.csv"},
+	{analysis: "uni", file: "example4.csv", prec: "example4.prec.csv"},
+	{analysis: "uni", file: "example4.csv", iip: func(w comm.JobSet) comm.IIP {
+		return comm.NewPrecautiousRM(w)
+	}},
+	{analysis: "por", file: "example.csv"},
+	{analysis: "por", file: "example4.csv", prec: "example4.prec.csv"},
+	{analysis: "por", file: "dagtask.yaml", tasks: true},
+	{analysis: "preemptive", file: "example.csv"},
+	{analysis: "preemptive", file: "example2.csv"},
+	{analysis: "preemptive", file: "example3.csv"},
+	{analysis: "global", file: "example.csv", cores: 2},
+	{analysis: "global", file: "example3.csv", cores: 2},
+	{analysis: "global", file: "example4.csv", cores: 3},
+}
+
+// sameResults reports the differences between result got and result want
+// of the same workload.
+func sameResults(t *testing.T, got, want *comm.AnalysisResult) {
+	t.Helper()
+	if !reflect.DeepEqual(got.ResponseTimes, want.ResponseTimes) {
+		t.Errorf("response times %v, want %v", got.ResponseTimes, want.ResponseTimes)
+	}
+	if !reflect.DeepEqual(got.Misses, want.Misses) {
+		t.Errorf("misses %v, want %v", got.Misses, want.Misses)
+	}
+	gotStats, wantStats := got.Statistics, want.Statistics
+	gotStats.CPUTime, wantStats.CPUTime = 0, 0
+	if gotStats != wantStats {
+		t.Errorf("statistics %v, want %v", gotStats, wantStats)
+	}
+}
+
+// TestThreadsDeterminism checks that a parallel exploration finds the same
+// response times, misses and graph as a sequential one.
+func TestThreadsDeterminism(t *testing.T) {
+	for _, w := range workloads {
+		t.Run(w.name(), func(t *testing.T) {
+			sequential := w.explore(t, func(opts *comm.AnalysisOptions) {})
+			parallel := w.explore(t, func(opts *comm.AnalysisOptions) {
+				opts.Threads = 4
+			})
+			sameResults(t, parallel, sequential)
+			if !reflect.DeepEqual(analysistest.DotLines(t, parallel), analysistest.DotLines(t, sequential)) {
+				t.Error("the graphs differ")
+			}
+		})
+	}
+}
+
+// TestNoGraph checks that an exploration without the graph finds the same
+// response times, misses and numbers of states and edges as one with it.
+func TestNoGraph(t *testing.T) {
+	for _, w := range workloads {
+		t.Run(w.name(), func(t *testing.T) {
+			withGraph := w.explore(t, func(opts *comm.AnalysisOptions) {})
+			noGraph := w.explore(t, func(opts *comm.AnalysisOptions) {
+				opts.NoGraph = true
+			})
+			sameResults(t, noGraph, withGraph)
+			if noGraph.Graph != nil {
+				t.Error("the graph is built")
+			}
+			if err := noGraph.WriteDotFile(filepath.Join(t.TempDir(), "graph.dot")); err == nil {
+				t.Error("the graph is written")
+			}
+		})
+	}
+}
+
+// TestReadyAfterPredecessors checks that a job whose predecessor is not
+// complete does not bound the start of other jobs: J2 has a higher priority
+// and is released before J1 can start at the latest, but it is not ready
+// until J1 completes.
+func TestReadyAfterPredecessors(t *testing.T) {
+	tests := []struct {
+		name     string
+		arrival1 comm.Interval
+		arrival2 comm.Interval
+		want     map[string]comm.Interval
+	}{
+		{"released while blocked", comm.Interval{Start: 2, End: 3}, comm.Interval{Start: 3, End: 3},
+			map[string]comm.Interval{"J1": {Start: 3, End: 5}, "J2": {Start: 4, End: 7}}},
+		{"released first", comm.Interval{Start: 0, End: 10}, comm.Interval{Start: 0, End: 0},
+			map[string]comm.Interval{"J1": {Start: 1, End: 12}, "J2": {Start: 2, End: 14}}},
+	}
+	for _, analysis := range []string{"uni", "por", "preemptive", "global"} {
+		for _, tt := range tests {
+			t.Run(analysis+" "+tt.name, func(t *testing.T) {
+				j1 := &comm.Job{Name: "J1", TaskID: 1, JobID: 1, Arrival: tt.arrival1, Cost: comm.Interval{Start: 1, End: 2}, Deadline: 20, Priority: 4}
+				j2 := &comm.Job{Name: "J2", TaskID: 2, JobID: 1, Arrival: tt.arrival2, Cost: comm.Interval{Start: 1, End: 2}, Deadline: 20, Priority: 3}
+				j2.AddPredecessor("J1")
+				workload := comm.JobSet{j1, j2}
+				if err := workload.Validate(comm.TimeModel{}); err != nil {
+					t.Fatal(err)
+				}
+				result := analyses[analysis](workload, comm.AnalysisOptions{Cores: 1, Logger: verbose.New("test")})
+				if !reflect.DeepEqual(result.ResponseTimes, tt.want) {
+					t.Errorf("response times %v, want %v", result.ResponseTimes, tt.want)
+				}
+			})
+		}
+	}
+}
+
+// chain returns n jobs that run one after the other. Their graph is a path,
+// whose exploration takes seconds for a few thousand jobs.
+func chain(n int) comm.JobSet {
+	var jobs comm.JobSet
+	for i := 0; i < n; i++ {
+		release := comm.Time(10 * i)
+		jobs = append(jobs, &comm.Job{Name: fmt.Sprintf("J1,%d", i+1), TaskID: 1, JobID: uint(i + 1),
+			Arrival: comm.Interval{Start: release, End: release}, Cost: comm.Interval{Start: 1, End: 2}, Deadline: release + 5, Priority: comm.Time(i + 1)})
+	}
+	return jobs
+}
+
+// TestAborted checks that every analysis stops at the depth limit and at
+// the timeout, and reports why.
+func TestAborted(t *testing.T) {
+	tests := []struct {
+		name          string
+		jobs          func(t *testing.T) comm.JobSet
+		with          func(*comm.AnalysisOptions)
+		timedOut      bool
+		depthExceeded bool
+		verdict       string
+	}{
+		{
+			name: "depth limit",
+			jobs: func(t *testing.T) comm.JobSet {
+				return analysistest.ReadWorkload(t, "example.csv", "")
+			},
+			with:          func(opts *comm.AnalysisOptions) { opts.MaxDepth = 2 },
+			depthExceeded: true,
+			verdict:       "depth exceeded",
+		},
+		{
+			name: "timeout",
+			jobs: func(t *testing.T) comm.JobSet {
+				return chain(2000)
+			},
+			with:     func(opts *comm.AnalysisOptions) { opts.Timeout = 1; opts.NoGraph = true },
+			timedOut: true,
+			verdict:  "timed out",
+		},
+	}
+	for _, analysis := range []string{"uni", "por", "preemptive", "global"} {
+		for _, tt := range tests {
+			t.Run(analysis+" "+tt.name, func(t *testing.T) {
+				jobs := tt.jobs(t)
+				if err := jobs.Validate(comm.TimeModel{}); err != nil {
+					t.Fatal(err)
+				}
+				opts := comm.AnalysisOptions{Cores: 1, EarlyExit: true, Logger: verbose.New("test")}
+				if analysis == "global" {
+					opts.Cores = 2
+				}
+				tt.with(&opts)
+				result := analyses[analysis](jobs, opts)
+				if !result.WasAborted() || result.TimedOut != tt.timedOut || result.DepthExceeded != tt.depthExceeded {
+					t.Errorf("aborted %v, timed out %v, depth exceeded %v, want true, %v, %v",
+						result.WasAborted(), result.TimedOut, result.DepthExceeded, tt.timedOut, tt.depthExceeded)
+				}
+				if result.IsSchedulable() || result.Verdict() != tt.verdict {
+					t.Errorf("verdict %s, want %s", result.Verdict(), tt.verdict)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package analysistest provides the workloads and helpers shared by the
+// tests of the analyses.
+package analysistest
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/comm"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Analysis explores the schedule-abstraction graph of job set w with one of
+// the analyses.
+type Analysis func(w comm.JobSet, opts comm.AnalysisOptions) *comm.AnalysisResult
+
+// examples is the directory of the example inputs, relative to the
+// directory of a package of lib.
+const examples = "../../example/"
+
+// ReadWorkload reads the job set file of the examples, with the precedence
+// constraints of prec if given.
+func ReadWorkload(t testing.TB, file, prec string) comm.JobSet {
+	t.Helper()
+	logger := verbose.New("test")
+	workload, err := comm.ReadJobSet(examples+file, comm.TimeModel{}, logger)
+	if err == nil && prec != "" {
+		err = comm.ReadPrecedence(examples+prec, &workload, comm.TimeModel{}, logger)
+	}
+	if err == nil {
+		err = workload.Validate(comm.TimeModel{})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return workload
+}
+
+// ReadTasks expands the yaml task set file of the examples over its
+// hyperperiod.
+func ReadTasks(t testing.TB, file string) comm.JobSet {
+	t.Helper()
+	taskSet, err := comm.ReadTaskSetYAML(examples+file, comm.TimeModel{}, verbose.New("test"))
+	var workload comm.JobSet
+	if err == nil {
+		workload, err = taskSet.Expand(0, comm.TimeModel{})
+	}
+	if err == nil {
+		err = workload.Validate(comm.TimeModel{})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return workload
+}
+
+// DotLines returns the sorted lines of the graph of r in DOT format, whose
+// order is not defined.
+func DotLines(t testing.TB, r *comm.AnalysisResult) []string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "graph.dot")
+	if err := r.WriteDotFile(filename); err != nil {
+		t.Fatal(err)
+	}
+	dot, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(dot), "\n")
+	sort.Strings(lines)
+	return lines
+}
@@ -0,0 +1,272 @@
+package comm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lfkeitel/verbose"
+	"io"
+	"os"
+	"sort"
+)
+
+var errNoGraph = errors.New("the graph was not built")
+
+// AnalysisOptions configures a single exploration.
+type AnalysisOptions struct {
+	// Timeout is the wall-clock limit of the exploration in seconds (0: no limit).
+	Timeout uint
+	// MaxDepth is the depth at which the exploration stops (0: no limit).
+	MaxDepth uint
+	// EarlyExit stops the exploration at the first deadline miss. Without
+	// it, the whole graph is explored and every miss is recorded.
+	EarlyExit bool
+	// Naive disables the merging of states.
+	Naive bool
+	// Cores is the number of identical cores (global analyses only).
+	Cores uint
+	// IIP is the idle-time insertion policy (uniprocessor analyses only).
+	IIP IIP
+	// LimitedPreemptive allows jobs to be preempted between their segments
+	// (non-preemptive uniprocessor analysis only).
+	LimitedPreemptive bool
+	// PorPriorityOrder adds interfering jobs to a reduction set by priority
+	// instead of release order (partial-order reduction only).
+	PorPriorityOrder bool
+	// NoGraph computes the response times without building the
+	// schedule-abstraction graph. Only the frontier of the exploration is
+	// kept in memory, and the result carries no graph.
+	NoGraph bool
+	// Threads is the number of goroutines that expand the states of one
+	// depth concurrently (0 or 1: sequential exploration). The result does
+	// not depend on the number of threads.
+	Threads int
+	// TimeModel is the time model of the job set. It tells whether strict
+	// bounds are open and how the times of the graph are formatted.
+	TimeModel TimeModel
+	// Logger receives the log messages of the exploration.
+	Logger *verbose.Logger
+}
+
+// MissedDeadline is a dispatch after which a job can finish after its
+// deadline, or a job that cannot be dispatched in a dead-end state.
+type MissedDeadline struct {
+	// Job is the dispatched job, which is a segment of a job of the workload
+	// for self-suspending and limited-preemptive jobs.
+	Job Job
+	// State is the name of the state in which the job is dispatched.
+	State string
+	// FinishTime is the finish time of the job after this dispatch, or
+	// Never() in a dead end.
+	FinishTime Interval
+}
+
+// DeadEndMisses returns the misses of the jobs of workload w that are not
+// dispatched in the dead-end state named state, whose dispatched jobs are
+// the indices in dispatched. These jobs never finish. A job split into
+// segments is reported once, by its last segment, which has the name of the
+// job.
+func DeadEndMisses(w JobSet, dispatched JobBitSet, state string) []MissedDeadline {
+	var misses []MissedDeadline
+	byJob := make(map[[2]uint]int)
+	for _, j := range w {
+		if dispatched.Contains(j.Index) {
+			continue
+		}
+		m := MissedDeadline{Job: *j, State: state, FinishTime: Never()}
+		if k, ok := byJob[[2]uint{j.TaskID, j.JobID}]; ok {
+			misses[k] = m
+		} else {
+			byJob[[2]uint{j.TaskID, j.JobID}] = len(misses)
+			misses = append(misses, m)
+		}
+	}
+	return misses
+}
+
+func (m MissedDeadline) String() string {
+	return m.Format(TimeModel{})
+}
+
+// Format describes the miss with the times formatted in the time model tm.
+func (m MissedDeadline) Format(tm TimeModel) string {
+	if m.FinishTime == Never() {
+		return fmt.Sprintf("%s cannot be dispatched in %s, which is a dead end, and misses its deadline %s", m.Job.Name, m.State, m.Job.Deadline.Format(tm))
+	}
+	return fmt.Sprintf("%s dispatched in %s finishes in %s, after its deadline %s", m.Job.Name, m.State, m.FinishTime.Format(tm), m.Job.Deadline.Format(tm))
+}
+
+// JobMiss is a job of the workload whose worst-case completion time is
+// after its deadline.
+type JobMiss struct {
+	Job  *Job
+	WCCT Time
+}
+
+// Overshoot returns the time by which the job can miss its deadline, which
+// is Infinity() if it may never finish.
+func (m JobMiss) Overshoot() Time {
+	if m.WCCT == Infinity() {
+		return m.WCCT
+	}
+	return m.WCCT - m.Job.Deadline
+}
+
+// TaskMisses counts the jobs of a task that can miss their deadline.
+type TaskMisses struct {
+	TaskID uint
+	// Jobs is the number of jobs of the task, and Misses the number of
+	// those that can miss their deadline.
+	Jobs         int
+	Misses       int
+	MaxOvershoot Time
+}
+
+// AnalysisResult is the outcome of an exploration.
+type AnalysisResult struct {
+	Workload JobSet
+	// TimeModel is the time model of the times of the result.
+	TimeModel TimeModel
+	// ResponseTimes maps the names of the jobs to their finish times. Jobs
+	// that an exploration stopped early never reached have no entry.
+	ResponseTimes map[string]Interval
+	DeadlineMiss  bool
+	// Misses lists the dispatches after which a job can miss its deadline,
+	// in the order in which they were found. With early exit, the
+	// exploration stops at the first one.
+	Misses        []MissedDeadline
+	Aborted       bool
+	TimedOut      bool
+	DepthExceeded bool
+	Statistics    Statistics
+	// Graph is the schedule-abstraction graph, or nil if it was not built.
+	Graph *DAG
+}
+
+// IsSchedulable reports whether the exploration completed without finding a
+// possible deadline miss.
+func (r *AnalysisResult) IsSchedulable() bool {
+	return !r.DeadlineMiss && !r.TimedOut && !r.DepthExceeded
+}
+
+// Verdict summarises the outcome of the exploration in a few words.
+func (r *AnalysisResult) Verdict() string {
+	if r.TimedOut {
+		return "timed out"
+	} else if r.DepthExceeded {
+		return "depth exceeded"
+	} else if !r.IsSchedulable() {
+		return "unschedulable"
+	}
+	return "schedulable"
+}
+
+// WasAborted reports whether the exploration stopped before exploring the
+// whole graph.
+func (r *AnalysisResult) WasAborted() bool {
+	return r.Aborted
+}
+
+func (r *AnalysisResult) Stats() Statistics {
+	return r.Statistics
+}
+
+// JobMisses returns the jobs whose worst-case completion time is after their
+// deadline, in the order of the workload. After an early exit, these are
+// only the jobs found before the exploration stopped. The worst-case
+// completion time of a job that a dead end strands is Infinity().
+func (r *AnalysisResult) JobMisses() []JobMiss {
+	var misses []JobMiss
+	for _, j := range r.Workload {
+		finishTime, ok := r.ResponseTimes[j.Name]
+		if ok && j.MayMissDeadline(finishTime) {
+			misses = append(misses, JobMiss{Job: j, WCCT: finishTime.End})
+		}
+	}
+	return misses
+}
+
+// TaskMisses returns, for every task with a job that can miss its deadline,
+// the number of such jobs, ordered by task ID.
+func (r *AnalysisResult) TaskMisses() []TaskMisses {
+	byTask := make(map[uint]*TaskMisses)
+	for _, m := range r.JobMisses() {
+		t, ok := byTask[m.Job.TaskID]
+		if !ok {
+			t = &TaskMisses{TaskID: m.Job.TaskID, MaxOvershoot: m.Overshoot()}
+			byTask[m.Job.TaskID] = t
+		}
+		t.Misses++
+		t.MaxOvershoot = Maximum(t.MaxOvershoot, m.Overshoot())
+	}
+
+	var tasks []TaskMisses
+	for _, j := range r.Workload {
+		if t, ok := byTask[j.TaskID]; ok {
+			t.Jobs++
+		}
+	}
+	for _, t := range byTask {
+		tasks = append(tasks, *t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].TaskID < tasks[j].TaskID
+	})
+	return tasks
+}
+
+func (r *AnalysisResult) PrintResponseTimes() {
+	r.FprintResponseTimes(os.Stdout)
+}
+
+// FprintResponseTimes prints the response times of the jobs to w. The jobs
+// that the exploration never reached are printed as unexplored.
+func (r *AnalysisResult) FprintResponseTimes(w io.Writer) {
+	fmt.Fprintln(w, "Response times:")
+	fmt.Fprintln(w, "Name: I[BCCT,WCCT]")
+
+	for _, j := range r.Workload {
+		if finishTime, ok := r.ResponseTimes[j.Name]; ok {
+			fmt.Fprintln(w, j.Name, ": ", finishTime.Format(r.TimeModel))
+		} else {
+			fmt.Fprintln(w, j.Name, ": ", "unexplored")
+		}
+	}
+}
+
+func (r *AnalysisResult) WriteResponseTimes(filePath string) error {
+	return WriteResponseTimes(filePath, r.ResponseTimes, r.Workload, r.TimeModel)
+}
+
+// WriteJSON writes the verdict, the response times and the statistics of
+// the exploration to filePath, together with the given analysis options.
+func (r *AnalysisResult) WriteJSON(filePath string, options interface{}) error {
+	return WriteResultJSON(filePath, r, options)
+}
+
+// WriteMisses writes the jobs that can miss their deadline to filePath in
+// csv format.
+func (r *AnalysisResult) WriteMisses(filePath string) error {
+	return WriteMisses(filePath, r)
+}
+
+// WriteMissesJSON writes the jobs that can miss their deadline and the
+// number of such jobs per task to filePath in json format.
+func (r *AnalysisResult) WriteMissesJSON(filePath string) error {
+	return WriteMissesJSON(filePath, r)
+}
+
+func (r *AnalysisResult) MakeDotFile(filePath string) error {
+	if r.Graph == nil {
+		return errNoGraph
+	}
+	return r.Graph.MakeDot(filePath)
+}
+
+// WriteDotFile writes the graph of the exploration to filePath, or to the
+// standard output for "-". It fails if the graph was not built.
+func (r *AnalysisResult) WriteDotFile(filePath string) error {
+	if r.Graph == nil {
+		return errNoGraph
+	}
+	return r.Graph.WriteDot(filePath)
+}
@@ -0,0 +1,39 @@
+package comm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeadEndMisses(t *testing.T) {
+	j1 := &Job{Name: "J1", TaskID: 1, JobID: 1, Deadline: 10, Segments: []Interval{{Start: 1, End: 2}, {Start: 1, End: 2}}}
+	j2 := &Job{Name: "J2", TaskID: 2, JobID: 1, Deadline: 20}
+	w := JobSet{j1, j2}.SplitSegments(true)
+	w.IndexJobs()
+
+	tests := []struct {
+		name       string
+		dispatched []int
+		want       []string
+	}{
+		{name: "nothing dispatched", want: []string{"J1", "J2"}},
+		{name: "first segment dispatched", dispatched: []int{0}, want: []string{"J1", "J2"}},
+		{name: "job dispatched", dispatched: []int{0, 1}, want: []string{"J2"}},
+		{name: "all dispatched", dispatched: []int{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatched := NewJobBitSet(len(w)).With(tt.dispatched...)
+			var got []string
+			for _, m := range DeadEndMisses(w, dispatched, "S3") {
+				if m.State != "S3" || m.FinishTime != Never() {
+					t.Errorf("%s: state %s, finish time %v", m.Job.Name, m.State, m.FinishTime)
+				}
+				got = append(got, m.Job.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stranded jobs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
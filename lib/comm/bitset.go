@@ -0,0 +1,97 @@
+package comm
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// JobBitSet is a set of jobs, given by their indices (see JobSet.IndexJobs).
+// A JobBitSet is not modified after it has been made; With returns a new set.
+type JobBitSet []uint64
+
+// NewJobBitSet returns an empty set for a job set of n jobs.
+func NewJobBitSet(n int) JobBitSet {
+	return make(JobBitSet, (n+63)/64)
+}
+
+// Contains reports whether the job with the given index is in b.
+func (b JobBitSet) Contains(index int) bool {
+	return b[index/64]&(1<<(uint(index)%64)) != 0
+}
+
+// ContainsAll reports whether all the jobs with the given indices are in b.
+func (b JobBitSet) ContainsAll(indices []int) bool {
+	for _, index := range indices {
+		if !b.Contains(index) {
+			return false
+		}
+	}
+	return true
+}
+
+// With returns a copy of b that also contains the jobs with the given
+// indices.
+func (b JobBitSet) With(indices ...int) JobBitSet {
+	c := make(JobBitSet, len(b))
+	copy(c, b)
+	for _, index := range indices {
+		c[index/64] |= 1 << (uint(index) % 64)
+	}
+	return c
+}
+
+// Len returns the number of jobs in b.
+func (b JobBitSet) Len() int {
+	n := 0
+	for _, w := range b {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Equal reports whether b and other contain the same jobs.
+func (b JobBitSet) Equal(other JobBitSet) bool {
+	if len(b) != len(other) {
+		return false
+	}
+	for i, w := range b {
+		if w != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns the FNV-1a hash of the words of b.
+func (b JobBitSet) Hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, w := range b {
+		h ^= w
+		h *= 1099511628211
+	}
+	return h
+}
+
+// Jobs returns the jobs of S that are in b.
+func (b JobBitSet) Jobs(S JobSet) JobSet {
+	var jobs JobSet
+	for _, j := range S {
+		if b.Contains(j.Index) {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+func (b JobBitSet) String() string {
+	var indices []string
+	for i, w := range b {
+		for w != 0 {
+			bit := bits.TrailingZeros64(w)
+			indices = append(indices, fmt.Sprint(i*64+bit))
+			w &= w - 1
+		}
+	}
+	return strings.Join(indices, ", ")
+}
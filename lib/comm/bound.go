@@ -0,0 +1,78 @@
+package comm
+
+// Bound is an upper or a lower bound on a time. An open bound excludes Time
+// itself. Open bounds only occur in the dense time model, which has no latest
+// time strictly before a given time; in the discrete model, a strict bound is
+// moved by one time unit instead. Where an open bound cannot be kept track
+// of, its Time is a sound closed bound.
+type Bound struct {
+	Time Time
+	Open bool
+}
+
+// At returns the closed bound t.
+func At(t Time) Bound {
+	return Bound{Time: t}
+}
+
+// Before returns the upper bound of the times strictly before t in the time
+// model m.
+func (m TimeModel) Before(t Time) Bound {
+	if t == Infinity() {
+		return At(t)
+	}
+	if m.Dense {
+		return Bound{Time: t, Open: true}
+	}
+	return Bound{Time: t - 1}
+}
+
+// After returns the lower bound of the times strictly after t in the time
+// model m.
+func (m TimeModel) After(t Time) Bound {
+	if t == Infinity() {
+		return At(t)
+	}
+	if m.Dense {
+		return Bound{Time: t, Open: true}
+	}
+	return Bound{Time: t + 1}
+}
+
+// Add returns bound b moved by d. An infinite bound stays infinite.
+func (b Bound) Add(d Time) Bound {
+	return Bound{Time: plus(b.Time, d), Open: b.Open}
+}
+
+// Covers reports whether time t is within the upper bound b.
+func (b Bound) Covers(t Time) bool {
+	return t < b.Time || t == b.Time && !b.Open
+}
+
+// MinUpper returns the tighter of the upper bounds b1 and b2.
+func MinUpper(b1, b2 Bound) Bound {
+	if b1.Time < b2.Time || b1.Time == b2.Time && b1.Open {
+		return b1
+	}
+	return b2
+}
+
+// MaxLower returns the tighter of the lower bounds b1 and b2.
+func MaxLower(b1, b2 Bound) Bound {
+	if b1.Time > b2.Time || b1.Time == b2.Time && b1.Open {
+		return b1
+	}
+	return b2
+}
+
+func (b Bound) String() string {
+	return b.Format(TimeModel{})
+}
+
+// Format formats b in time units of the time model m.
+func (b Bound) Format(m TimeModel) string {
+	if b.Open {
+		return b.Time.Format(m) + " (open)"
+	}
+	return b.Time.Format(m)
+}
@@ -0,0 +1,64 @@
+package comm
+
+import "testing"
+
+func TestBeforeAfter(t *testing.T) {
+	dense := TimeModel{Dense: true}
+	tests := []struct {
+		name string
+		got  Bound
+		want Bound
+	}{
+		{"discrete before", TimeModel{}.Before(10), At(9)},
+		{"discrete after", TimeModel{}.After(10), At(11)},
+		{"dense before", dense.Before(10), Bound{Time: 10, Open: true}},
+		{"dense after", dense.After(10), Bound{Time: 10, Open: true}},
+		{"add keeps open", dense.Before(10).Add(5), Bound{Time: 15, Open: true}},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestCovers(t *testing.T) {
+	tests := []struct {
+		b    Bound
+		t    Time
+		want bool
+	}{
+		{At(10), 9, true},
+		{At(10), 10, true},
+		{At(10), 11, false},
+		{Bound{Time: 10, Open: true}, 9, true},
+		{Bound{Time: 10, Open: true}, 10, false},
+	}
+	for _, tt := range tests {
+		if got := tt.b.Covers(tt.t); got != tt.want {
+			t.Errorf("%v.Covers(%d) = %t, want %t", tt.b, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestTighterBounds(t *testing.T) {
+	open := Bound{Time: 10, Open: true}
+	tests := []struct {
+		name string
+		got  Bound
+		want Bound
+	}{
+		{"min upper", MinUpper(At(10), At(12)), At(10)},
+		{"min upper open", MinUpper(At(10), open), open},
+		{"min upper open first", MinUpper(open, At(10)), open},
+		{"min upper earlier closed", MinUpper(At(9), open), At(9)},
+		{"max lower", MaxLower(At(10), At(12)), At(12)},
+		{"max lower open", MaxLower(At(10), open), open},
+		{"max lower later closed", MaxLower(At(11), open), At(11)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -4,7 +4,6 @@ package comm
 import (
 	"fmt"
 	"github.com/google/uuid"
-	"log"
 	"os"
 	"sync"
 )
@@ -863,7 +862,13 @@ func copyMap(in map[interface{}]struct{}) map[interface{}]struct{} {
 	return out
 }
 
-func (d *DAG) MakeDot(fileName string) {
+func (d *DAG) MakeDot(fileName string) error {
+	return d.WriteDot(fileName + ".dot")
+}
+
+// WriteDot writes the graph in DOT format to the file fileName, or to the
+// standard output for "-".
+func (d *DAG) WriteDot(fileName string) error {
 	dotOut := "digraph {\n"
 	dotOut += "\tgraph [fontname=Ubuntu];\n"
 	dotOut += "\tnode [fontname=Ubuntu];\n"
@@ -885,20 +890,10 @@ func (d *DAG) MakeDot(fileName string) {
 	}
 	dotOut += "}"
 
-	f, err := os.Create(fileName + ".dot")
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer f.Close()
-
-	_, err2 := f.WriteString(dotOut)
-
-	if err2 != nil {
-		log.Fatal(err2)
-	}
-
+	return writeOutput(fileName, func(f *os.File) error {
+		_, err := f.WriteString(dotOut)
+		return err
+	})
 }
 
 /***************************
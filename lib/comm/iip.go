@@ -0,0 +1,161 @@
+package comm
+
+import "sort"
+
+// ScheduleState is the view of an analysis state that an idle-time insertion
+// policy may inspect.
+type ScheduleState interface {
+	Incomplete(j Job) bool
+}
+
+// IIP is an idle-time insertion policy. It may keep a ready job from being
+// dispatched, i.e. insert idle time on purpose.
+type IIP interface {
+	// CanBlock reports whether the policy can ever delay a ready job.
+	CanBlock() bool
+	// LatestStart returns the latest time at which job j may still be
+	// started in state s, given that it becomes eligible at time t.
+	LatestStart(j Job, t Time, s ScheduleState) Time
+}
+
+// IIPEligible reports whether job j may be started at time t in state s.
+func IIPEligible(iip IIP, s ScheduleState, j Job, t Time) bool {
+	return !iip.CanBlock() || iip.LatestStart(j, t, s) >= t
+}
+
+// NullIIP never inserts idle time (work-conserving scheduling).
+type NullIIP struct{}
+
+func (NullIIP) CanBlock() bool {
+	return false
+}
+
+func (NullIIP) LatestStart(j Job, t Time, s ScheduleState) Time {
+	return Infinity()
+}
+
+// PrecautiousRM is the Precautious-RM policy: a job that does not belong to
+// the highest priority level may only start if the next incomplete
+// highest-priority job can still finish by its deadline afterwards.
+type PrecautiousRM struct {
+	maxPriority Time
+	hpJobs      JobSet
+}
+
+func NewPrecautiousRM(jobs JobSet) *PrecautiousRM {
+	p := &PrecautiousRM{}
+	if jobs.Empty() {
+		return p
+	}
+
+	p.maxPriority = jobs[0].Priority
+	for _, j := range jobs {
+		if j.PriorityExceeds(p.maxPriority) {
+			p.maxPriority = j.Priority
+		}
+	}
+	for _, j := range jobs {
+		if j.Priority == p.maxPriority {
+			p.hpJobs = append(p.hpJobs, j)
+		}
+	}
+	return p
+}
+
+func (p *PrecautiousRM) CanBlock() bool {
+	return true
+}
+
+func (p *PrecautiousRM) LatestStart(j Job, t Time, s ScheduleState) Time {
+	// never block the highest-priority jobs
+	if j.Priority == p.maxPriority {
+		return Infinity()
+	}
+
+	// find the next highest-priority job that is not yet scheduled
+	var next *Job
+	for _, h := range p.hpJobs {
+		if h.GetLatestArrival() < t || !s.Incomplete(*h) {
+			continue
+		}
+		if next == nil || h.GetLatestArrival() < next.GetLatestArrival() {
+			next = h
+		}
+	}
+
+	if next == nil {
+		return Infinity()
+	}
+	return next.Deadline - next.GetMaximalCost() - j.GetMaximalCost()
+}
+
+// CriticalWindowEDF is the Critical-Window EDF policy: a job may only start
+// if, afterwards, the next job of every other task can still be scheduled
+// in EDF order before its deadline.
+type CriticalWindowEDF struct {
+	jobsByEarliestArrival JobSet
+}
+
+func NewCriticalWindowEDF(jobs JobSet) *CriticalWindowEDF {
+	byArrival := make(JobSet, len(jobs))
+	copy(byArrival, jobs)
+	byArrival.SortByEarliestArrival()
+	return &CriticalWindowEDF{jobsByEarliestArrival: byArrival}
+}
+
+func (cw *CriticalWindowEDF) CanBlock() bool {
+	return true
+}
+
+func (cw *CriticalWindowEDF) LatestStart(j Job, t Time, s ScheduleState) Time {
+	influencing := cw.influencingJobs(j, t, s)
+
+	// walk from the latest to the earliest deadline
+	latest := Infinity()
+	for i := len(influencing) - 1; i >= 0; i-- {
+		latest = plus(Minimum(latest, influencing[i].Deadline), -influencing[i].GetMaximalCost())
+	}
+	// j must complete before the critical window starts
+	return plus(latest, -j.GetMaximalCost())
+}
+
+// influencingJobs returns, for every task, the first incomplete job (other
+// than j) that is pending at time t or released inside the critical window,
+// sorted by deadline.
+func (cw *CriticalWindowEDF) influencingJobs(j Job, t Time, s ScheduleState) JobSet {
+	byTask := make(map[uint]*Job)
+	// jobs released while j executes are blocked by it
+	latestDeadline := t + j.GetMaximalCost()
+
+	for _, jt := range cw.jobsByEarliestArrival {
+		if jt.GetEarliestArrival() > t {
+			// look into future releases only as far as the critical window reaches
+			if jt.GetEarliestArrival() > latestDeadline {
+				break
+			}
+		}
+
+		if jt.SameJob(j) || !s.Incomplete(*jt) {
+			continue
+		}
+
+		if _, ok := byTask[jt.TaskID]; ok {
+			continue
+		}
+
+		byTask[jt.TaskID] = jt
+		latestDeadline = Maximum(latestDeadline, jt.Deadline)
+	}
+
+	var influencing JobSet
+	for _, jt := range byTask {
+		influencing = append(influencing, jt)
+	}
+	sort.Slice(influencing, func(a, b int) bool {
+		if influencing[a].Deadline == influencing[b].Deadline {
+			return influencing[a].Name < influencing[b].Name
+		}
+		return influencing[a].Deadline < influencing[b].Deadline
+	})
+	return influencing
+}
@@ -0,0 +1,105 @@
+package comm_test
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/comm"
+	"go-test/lib/uni-non-preemptive"
+	"reflect"
+	"testing"
+)
+
+// allIncomplete is a schedule state in which no job has completed.
+type allIncomplete struct{}
+
+func (allIncomplete) Incomplete(j comm.Job) bool {
+	return true
+}
+
+// idleJobs returns a job set in which the low-priority job L, released
+// first, makes the high-priority job H miss its deadline unless the
+// processor idles until H is released.
+func idleJobs() comm.JobSet {
+	return comm.JobSet{
+		{Name: "H", TaskID: 1, JobID: 1, Arrival: comm.Interval{Start: 3, End: 3}, Cost: comm.Interval{Start: 2, End: 2}, Deadline: 5, Priority: 1},
+		{Name: "L", TaskID: 2, JobID: 1, Arrival: comm.Interval{Start: 0, End: 0}, Cost: comm.Interval{Start: 4, End: 4}, Deadline: 20, Priority: 2},
+	}
+}
+
+// TestLatestStart checks the latest start times of the jobs of idleJobs at
+// their release.
+func TestLatestStart(t *testing.T) {
+	jobs := idleJobs()
+	high, low := *jobs[0], *jobs[1]
+	tests := []struct {
+		name      string
+		iip       comm.IIP
+		canBlock  bool
+		high, low comm.Time
+	}{
+		{name: "none", iip: comm.NullIIP{}, canBlock: false, high: comm.Infinity(), low: comm.Infinity()},
+		// L must start by 5 - 2 - 4 for H to finish by its deadline
+		{name: "p-rm", iip: comm.NewPrecautiousRM(jobs), canBlock: true, high: comm.Infinity(), low: -1},
+		// H must leave room for L before the deadline 20 of L
+		{name: "cw", iip: comm.NewCriticalWindowEDF(jobs), canBlock: true, high: 14, low: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.iip.CanBlock(); got != tt.canBlock {
+				t.Errorf("CanBlock() = %v, want %v", got, tt.canBlock)
+			}
+			if got := tt.iip.LatestStart(high, 3, allIncomplete{}); got != tt.high {
+				t.Errorf("LatestStart(H) = %v, want %v", got, tt.high)
+			}
+			if got := tt.iip.LatestStart(low, 0, allIncomplete{}); got != tt.low {
+				t.Errorf("LatestStart(L) = %v, want %v", got, tt.low)
+			}
+			if got := comm.IIPEligible(tt.iip, allIncomplete{}, low, 0); got != !tt.canBlock {
+				t.Errorf("L eligible at 0: %v, want %v", got, !tt.canBlock)
+			}
+		})
+	}
+}
+
+// TestIdleTimeInsertion checks that both policies delay L until H completes,
+// so that H meets its deadline.
+func TestIdleTimeInsertion(t *testing.T) {
+	tests := []struct {
+		name string
+		iip  func(comm.JobSet) comm.IIP
+		miss bool
+		want map[string]comm.Interval
+	}{
+		{name: "none", iip: func(comm.JobSet) comm.IIP { return comm.NullIIP{} }, miss: true,
+			want: map[string]comm.Interval{"H": {Start: 6, End: 6}, "L": {Start: 4, End: 4}}},
+		{name: "p-rm", iip: func(w comm.JobSet) comm.IIP { return comm.NewPrecautiousRM(w) },
+			want: map[string]comm.Interval{"H": {Start: 5, End: 5}, "L": {Start: 9, End: 9}}},
+		{name: "cw", iip: func(w comm.JobSet) comm.IIP { return comm.NewCriticalWindowEDF(w) },
+			want: map[string]comm.Interval{"H": {Start: 5, End: 5}, "L": {Start: 9, End: 9}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := idleJobs()
+			if err := jobs.Validate(comm.TimeModel{}); err != nil {
+				t.Fatal(err)
+			}
+			opts := comm.AnalysisOptions{Logger: verbose.New("test"), IIP: tt.iip(jobs)}
+			result := uni_non_preemptive.NewSpace(jobs, opts).Explore()
+			if result.IsSchedulable() == tt.miss {
+				t.Errorf("schedulable: %v, want %v", result.IsSchedulable(), !tt.miss)
+			}
+			if !reflect.DeepEqual(result.ResponseTimes, tt.want) {
+				t.Errorf("response times %v, want %v", result.ResponseTimes, tt.want)
+			}
+		})
+	}
+}
+
+// TestCriticalWindowWithoutOtherJobs checks that a job that no other job
+// influences may start at any time.
+func TestCriticalWindowWithoutOtherJobs(t *testing.T) {
+	j := &comm.Job{Name: "J1", TaskID: 1, JobID: 1, Arrival: comm.Interval{Start: 0, End: 0}, Cost: comm.Interval{Start: 1, End: 2}, Deadline: 10}
+	cw := comm.NewCriticalWindowEDF(comm.JobSet{j})
+	if got := cw.LatestStart(*j, 0, allIncomplete{}); got != comm.Infinity() {
+		t.Errorf("LatestStart = %v, want Infinity()", got)
+	}
+}
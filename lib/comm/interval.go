@@ -1,8 +1,50 @@
 package comm
 
+// Interval is the interval of times from Start to End. Its end points are
+// closed unless StartOpen or EndOpen is set, which only happens in the dense
+// time model (see Bound).
 type Interval struct {
-	Start Time
-	End   Time
+	Start     Time
+	End       Time
+	StartOpen bool
+	EndOpen   bool
+}
+
+// Between returns the interval of times within the lower bound from and the
+// upper bound until.
+func Between(from, until Bound) Interval {
+	return Interval{Start: from.Time, End: until.Time, StartOpen: from.Open, EndOpen: until.Open}
+}
+
+// Never returns the finish time of a job that cannot finish, because it
+// cannot be dispatched in a dead-end state of the exploration.
+func Never() Interval {
+	return Interval{Start: Infinity(), End: Infinity()}
+}
+
+// Lower returns the lower bound of i.
+func (i Interval) Lower() Bound {
+	return Bound{Time: i.Start, Open: i.StartOpen}
+}
+
+// Upper returns the upper bound of i.
+func (i Interval) Upper() Bound {
+	return Bound{Time: i.End, Open: i.EndOpen}
+}
+
+// Empty reports whether i contains no time.
+func (i Interval) Empty() bool {
+	return i.Start > i.End || i.Start == i.End && (i.StartOpen || i.EndOpen)
+}
+
+// Add returns the interval of the sums of a time of i and a time of other.
+func (i Interval) Add(other Interval) Interval {
+	return Interval{
+		Start:     plus(i.Start, other.Start),
+		End:       plus(i.End, other.End),
+		StartOpen: i.StartOpen || other.StartOpen,
+		EndOpen:   i.EndOpen || other.EndOpen,
+	}
 }
 
 func (i Interval) Intersects(j Interval) bool {
@@ -38,9 +80,26 @@ func (i Interval) Length() int {
 }
 
 func (i Interval) String() string {
-	return "I[" + i.Start.String() + "," + i.End.String() + "]"
+	return i.Format(TimeModel{})
+}
+
+// Format formats i in time units of the time model m.
+func (i Interval) Format(m TimeModel) string {
+	from, until := "[", "]"
+	if i.StartOpen {
+		from = "("
+	}
+	if i.EndOpen {
+		until = ")"
+	}
+	return "I" + from + i.Start.Format(m) + "," + i.End.Format(m) + until
 }
 
+// Widen returns the smallest interval that contains both i and other. An end
+// point is open only if it is open in both intervals.
 func (i Interval) Widen(other Interval) Interval {
-	return Interval{Start: Minimum(i.Start, other.Start), End: Maximum(i.End, other.End)}
+	w := Interval{Start: Minimum(i.Start, other.Start), End: Maximum(i.End, other.End)}
+	w.StartOpen = (i.Start > w.Start || i.StartOpen) && (other.Start > w.Start || other.StartOpen)
+	w.EndOpen = (i.End < w.End || i.EndOpen) && (other.End < w.End || other.EndOpen)
+	return w
 }
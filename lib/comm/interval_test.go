@@ -0,0 +1,77 @@
+package comm
+
+import "testing"
+
+func TestIntervalEmpty(t *testing.T) {
+	tests := []struct {
+		i    Interval
+		want bool
+	}{
+		{Interval{Start: 1, End: 2}, false},
+		{Interval{Start: 2, End: 2}, false},
+		{Interval{Start: 3, End: 2}, true},
+		{Interval{Start: 2, End: 2, StartOpen: true}, true},
+		{Interval{Start: 2, End: 2, EndOpen: true}, true},
+		{Interval{Start: 1, End: 2, StartOpen: true, EndOpen: true}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.i.Empty(); got != tt.want {
+			t.Errorf("%v.Empty() = %t, want %t", tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalAdd(t *testing.T) {
+	tests := []struct {
+		i, other Interval
+		want     Interval
+	}{
+		{Interval{Start: 1, End: 2}, Interval{Start: 3, End: 5}, Interval{Start: 4, End: 7}},
+		{Interval{Start: 1, End: 2, EndOpen: true}, Interval{Start: 3, End: 5}, Interval{Start: 4, End: 7, EndOpen: true}},
+		{Interval{Start: 1, End: 2}, Interval{Start: 3, End: 5, StartOpen: true}, Interval{Start: 4, End: 7, StartOpen: true}},
+		{Interval{Start: 1, End: Infinity()}, Interval{Start: 3, End: 5}, Interval{Start: 4, End: Infinity()}},
+	}
+	for _, tt := range tests {
+		if got := tt.i.Add(tt.other); got != tt.want {
+			t.Errorf("%v.Add(%v) = %v, want %v", tt.i, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalWiden(t *testing.T) {
+	tests := []struct {
+		i, other Interval
+		want     Interval
+	}{
+		{Interval{Start: 1, End: 3}, Interval{Start: 2, End: 5}, Interval{Start: 1, End: 5}},
+		// an end point that only one of the intervals reaches keeps its openness
+		{Interval{Start: 1, End: 3, StartOpen: true}, Interval{Start: 2, End: 5, EndOpen: true}, Interval{Start: 1, End: 5, StartOpen: true, EndOpen: true}},
+		// an end point is closed if one of the intervals contains it
+		{Interval{Start: 1, End: 5, EndOpen: true}, Interval{Start: 2, End: 5}, Interval{Start: 1, End: 5}},
+		{Interval{Start: 1, End: 5, StartOpen: true, EndOpen: true}, Interval{Start: 1, End: 5, StartOpen: true, EndOpen: true}, Interval{Start: 1, End: 5, StartOpen: true, EndOpen: true}},
+		{Interval{Start: 1, End: 5, StartOpen: true}, Interval{Start: 1, End: 2}, Interval{Start: 1, End: 5}},
+	}
+	for _, tt := range tests {
+		if got := tt.i.Widen(tt.other); got != tt.want {
+			t.Errorf("%v.Widen(%v) = %v, want %v", tt.i, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalFormat(t *testing.T) {
+	tests := []struct {
+		i    Interval
+		m    TimeModel
+		want string
+	}{
+		{Interval{Start: 1, End: 3}, TimeModel{}, "I[1,3]"},
+		{Interval{Start: 1000000, End: 3000000, EndOpen: true}, TimeModel{Dense: true}, "I[1.000000,3.000000)"},
+		{Interval{Start: 1000000, End: 3000000, StartOpen: true}, TimeModel{Dense: true}, "I(1.000000,3.000000]"},
+		{Never(), TimeModel{}, "I[inf,inf]"},
+	}
+	for _, tt := range tests {
+		if got := tt.i.Format(tt.m); got != tt.want {
+			t.Errorf("%#v.Format(%+v) = %q, want %q", tt.i, tt.m, got, tt.want)
+		}
+	}
+}
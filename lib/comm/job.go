@@ -15,6 +15,21 @@ type Job struct {
 	Deadline     Time
 	Priority     Time
 	Predecessors []string
+	// Segments are the non-preemptive segments of a limited-preemptive
+	// job. The job may only be preempted between two segments.
+	Segments []Interval
+	// Suspensions are the self-suspension intervals between the segments
+	// of a job, Suspensions[k] following Segments[k].
+	Suspensions []Interval
+	// PredecessorDelays are the delays between the completion of a
+	// predecessor and the release of the job, e.g., for messages or
+	// self-suspensions. Predecessors without a delay are not listed.
+	PredecessorDelays map[string]Interval
+	// Index is the position of the job in the analysed job set, and
+	// PredecessorIndices are the indices of its predecessors. Both are set
+	// by IndexJobs.
+	Index              int
+	PredecessorIndices []int
 }
 
 type JobSet []*Job
@@ -78,14 +93,48 @@ func (j Job) PriorityExceeds(otherPriority Time) bool {
 }
 
 func (j Job) ExceedsDeadline(now Time) bool {
-	return (j.Deadline < now) && (now-j.Deadline > DeadlineMissTolerance())
+	return j.Deadline < now
+}
+
+// MayMissDeadline reports whether j may finish after its deadline when it
+// finishes within finishTime. Only the upper bound matters: whether closed or
+// open, an upper bound at the deadline admits no time after it, while an open
+// upper bound after the deadline still admits the times between the deadline
+// and the bound.
+func (j Job) MayMissDeadline(finishTime Interval) bool {
+	return finishTime.Upper().Time > j.Deadline
 }
 
 func (j *Job) AddPredecessor(predecessor string) {
 	j.Predecessors = append(j.Predecessors, predecessor)
 }
 
-////////////////////////////////
+// AddPredecessorWithDelay adds a predecessor whose completion releases the
+// job after the given delay.
+func (j *Job) AddPredecessorWithDelay(predecessor string, delay Interval) {
+	j.AddPredecessor(predecessor)
+	if delay == (Interval{}) {
+		return
+	}
+	if j.PredecessorDelays == nil {
+		j.PredecessorDelays = make(map[string]Interval)
+	}
+	j.PredecessorDelays[predecessor] = delay
+}
+
+// GetPredecessorDelay returns the delay between the completion of
+// predecessor and the release of the job.
+func (j Job) GetPredecessorDelay(predecessor string) Interval {
+	return j.PredecessorDelays[predecessor]
+}
+
+// HasDelayedPredecessors reports whether the release of job j depends on
+// the completion time of some predecessor.
+func (j Job) HasDelayedPredecessors() bool {
+	return len(j.PredecessorDelays) > 0
+}
+
+// //////////////////////////////
 // Functions for jobset
 func (j JobSet) String() string {
 	var s string
@@ -220,7 +269,7 @@ func (S JobSet) Empty() bool {
 	return len(S) == 0
 }
 
-//SelectJobByReleaseOrder find job with the lowest release time
+// SelectJobByReleaseOrder find job with the lowest release time
 func (S *JobSet) SelectJobByReleaseOrder() *Job {
 	var job Job
 	for _, j := range *S {
@@ -272,17 +321,22 @@ func (S *JobSet) TopologicalSort() JobSet {
 	return sortedJobs
 }
 
+// SetArrivalTimeWithPrecedence shifts the arrival window of every job
+// after those of its predecessors, plus the delays of the precedence edges.
+// The jobs are visited in topological order, so that shifts propagate along
+// chains of jobs.
 func (S *JobSet) SetArrivalTimeWithPrecedence() {
-	for _, job := range *S {
+	for _, job := range S.TopologicalSort() {
 		maxEarliestArrival := job.GetEarliestArrival()
 		maxLatestArrival := job.GetLatestArrival()
-		for _, predJob := range job.GetPredecessors() {
-			predJob := S.GetByName(predJob)
-			if predJob.GetEarliestArrival() > maxEarliestArrival {
-				maxEarliestArrival = predJob.GetEarliestArrival()
+		for _, pred := range job.GetPredecessors() {
+			predJob := S.GetByName(pred)
+			delay := job.GetPredecessorDelay(pred)
+			if predJob.GetEarliestArrival()+delay.Min() > maxEarliestArrival {
+				maxEarliestArrival = predJob.GetEarliestArrival() + delay.Min()
 			}
-			if predJob.GetLatestArrival() > maxLatestArrival {
-				maxLatestArrival = predJob.GetLatestArrival()
+			if predJob.GetLatestArrival()+delay.Max() > maxLatestArrival {
+				maxLatestArrival = predJob.GetLatestArrival() + delay.Max()
 			}
 		}
 		job.Arrival.Start = maxEarliestArrival
@@ -296,6 +350,89 @@ func (S *JobSet) PreprocessJobs() {
 	S.SetArrivalTimeWithPrecedence()
 }
 
+// IndexJobs numbers the jobs of S by their position, so that sets of jobs
+// can be kept as a JobBitSet, and resolves the predecessors to indices.
+// Predecessors that are not in S are ignored.
+func (S JobSet) IndexJobs() {
+	indices := make(map[string]int, len(S))
+	for i, j := range S {
+		j.Index = i
+		indices[j.Name] = i
+	}
+	for _, j := range S {
+		j.PredecessorIndices = nil
+		for _, pred := range j.Predecessors {
+			if index, ok := indices[pred]; ok {
+				j.PredecessorIndices = append(j.PredecessorIndices, index)
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the job set whose jobs can be modified without
+// affecting the original ones.
+func (S JobSet) Clone() JobSet {
+	clone := make(JobSet, len(S))
+	for i, j := range S {
+		c := *j
+		c.Predecessors = append([]string(nil), j.Predecessors...)
+		c.Segments = append([]Interval(nil), j.Segments...)
+		c.Suspensions = append([]Interval(nil), j.Suspensions...)
+		c.PredecessorIndices = append([]int(nil), j.PredecessorIndices...)
+		if j.PredecessorDelays != nil {
+			c.PredecessorDelays = make(map[string]Interval, len(j.PredecessorDelays))
+			for pred, delay := range j.PredecessorDelays {
+				c.PredecessorDelays[pred] = delay
+			}
+		}
+		clone[i] = &c
+	}
+	return clone
+}
+
+// IsSelfSuspending reports whether job j suspends between its segments.
+func (j Job) IsSelfSuspending() bool {
+	return len(j.Suspensions) > 0
+}
+
+// SplitSegments returns a job set in which self-suspending jobs, and every
+// segmented job if all is set, are replaced by a chain of segment jobs.
+// Segment k is named "<job>#k", except for the last one, which keeps the
+// name of the job so that precedence constraints and response times refer
+// to it.
+func (S JobSet) SplitSegments(all bool) JobSet {
+	var split JobSet
+	for _, j := range S.Clone() {
+		if len(j.Segments) < 2 || !all && !j.IsSelfSuspending() {
+			split = append(split, j)
+			continue
+		}
+
+		previous := ""
+		for k, segment := range j.Segments {
+			s := *j
+			s.Cost = segment
+			s.Segments = nil
+			s.Suspensions = nil
+			if k > 0 {
+				s.Predecessors = nil
+				s.PredecessorDelays = nil
+				if j.IsSelfSuspending() {
+					s.AddPredecessorWithDelay(previous, j.Suspensions[k-1])
+				} else {
+					s.AddPredecessor(previous)
+				}
+			}
+			if k < len(j.Segments)-1 {
+				s.Name = j.Name + "#" + fmt.Sprint(k+1)
+			}
+			split = append(split, &s)
+			previous = s.Name
+		}
+	}
+	return split
+}
+
 ////////////////////////////////
 // Functions for job queue
 
@@ -0,0 +1,24 @@
+package comm
+
+import "testing"
+
+func TestMayMissDeadline(t *testing.T) {
+	j := Job{Name: "J1", TaskID: 1, JobID: 1, Deadline: 10}
+	tests := []struct {
+		finishTime Interval
+		want       bool
+	}{
+		{finishTime: Interval{Start: 2, End: 9}, want: false},
+		{finishTime: Interval{Start: 2, End: 10}, want: false},
+		{finishTime: Interval{Start: 2, End: 10, EndOpen: true}, want: false},
+		{finishTime: Interval{Start: 2, End: 11}, want: true},
+		{finishTime: Interval{Start: 2, End: 11, EndOpen: true}, want: true},
+		{finishTime: Interval{Start: 10, End: 11, StartOpen: true, EndOpen: true}, want: true},
+		{finishTime: Interval{Start: 2, End: Infinity()}, want: true},
+	}
+	for _, tt := range tests {
+		if got := j.MayMissDeadline(tt.finishTime); got != tt.want {
+			t.Errorf("MayMissDeadline(%v) = %t, want %t", tt.finishTime, got, tt.want)
+		}
+	}
+}
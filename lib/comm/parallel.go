@@ -0,0 +1,35 @@
+package comm
+
+import "sync"
+
+// ParallelFor calls f(i) for every i in [0, n) on the given number of
+// goroutines. It returns once all calls have returned. With a single thread,
+// the calls are made in order on the calling goroutine.
+func ParallelFor(n int, threads int, f func(i int)) {
+	if threads <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+	if threads > n {
+		threads = n
+	}
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for t := 0; t < threads; t++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -1,148 +1,821 @@
 package comm
 
 import (
+	"bytes"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/lfkeitel/verbose"
 	"gopkg.in/yaml.v3"
-	"io/ioutil"
-	"log"
+	"io"
 	"os"
 	"strconv"
+	"strings"
 )
 
-func ReadJobSet(filename string, v *verbose.Logger) JobSet {
-	var jobs JobSet
-
-	csvFile, err := os.Open(filename)
+// csvFields reads the CSV records of filename after its header line and
+// calls parse for each of them. The errors of parse are located at the
+// position of the field they report.
+func csvFields(filename string, v *verbose.Logger, parse func(fields []string, fieldError func(i int, err error) error) error) error {
+	csvFile, err := openInput(filename)
 	if err != nil {
-		fmt.Println(err)
+		return err
 	}
 
 	v.Debug("Successfully Opened CSV file")
 
-	defer csvFile.Close()
+	defer closeInput(csvFile)
 
 	reader := csv.NewReader(csvFile)
 	reader.TrimLeadingSpace = true
 
 	// skip first line
 	if _, err := reader.Read(); err != nil {
-		v.Panic(err)
-		panic(err)
+		if err == io.EOF {
+			return &InputError{File: filename, Err: errors.New("missing header line")}
+		}
+		return fileError(filename, err)
+	}
+
+	for {
+		line, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fileError(filename, err)
+		}
+
+		fieldError := func(i int, err error) error {
+			l, c := reader.FieldPos(i)
+			return &InputError{File: filename, Line: l, Column: c, Err: err}
+		}
+		if err := parse(line, fieldError); err != nil {
+			return err
+		}
+	}
+}
+
+// openInput opens the input file filename, or returns the standard input
+// for "-".
+func openInput(filename string) (*os.File, error) {
+	if filename == "-" {
+		return os.Stdin, nil
+	}
+	return os.Open(filename)
+}
+
+func closeInput(f *os.File) {
+	if f != os.Stdin {
+		f.Close()
+	}
+}
+
+// readInput reads the whole input file filename, or the standard input for
+// "-".
+func readInput(filename string) ([]byte, error) {
+	if filename == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(filename)
+}
+
+// fileError locates the CSV parse errors in filename.
+func fileError(filename string, err error) error {
+	var parseError *csv.ParseError
+	if errors.As(err, &parseError) {
+		return &InputError{File: filename, Line: parseError.Line, Column: parseError.Column, Err: parseError.Err}
+	}
+	return &InputError{File: filename, Err: err}
+}
+
+func parseID(fields []string, i int, name string, fieldError func(int, error) error) (uint, error) {
+	id, err := strconv.ParseUint(fields[i], 10, 32)
+	if err != nil {
+		return 0, fieldError(i, fmt.Errorf("invalid %s %q", name, fields[i]))
+	}
+	return uint(id), nil
+}
+
+// parseTime parses the time of field i, which may have a unit suffix, e.g.,
+// "500us". Lower bounds are rounded down and upper bounds up.
+func parseTime(m TimeModel, fields []string, i int, name string, rounding Rounding, fieldError func(int, error) error) (Time, error) {
+	t, err := m.ParseTime(fields[i], rounding)
+	if err != nil {
+		return 0, fieldError(i, fmt.Errorf("%s: %v", name, err))
 	}
+	return t, nil
+}
 
-	csvLines, err := reader.ReadAll()
+func parsePriority(m TimeModel, fields []string, i int, fieldError func(int, error) error) (Time, error) {
+	p, err := m.ParsePriority(fields[i])
 	if err != nil {
-		v.Error(err)
+		return 0, fieldError(i, fmt.Errorf("Priority: %v", err))
 	}
+	return p, nil
+}
 
-	for _, line := range csvLines {
+func ReadJobSet(filename string, m TimeModel, v *verbose.Logger) (JobSet, error) {
+	var jobs JobSet
+	names := make(map[string]bool)
 
-		taskid, _ := strconv.ParseUint(line[0], 10, 32)
-		jobid, _ := strconv.ParseUint(line[1], 10, 32)
-		arrivalMin, _ := strconv.Atoi(line[2])
-		arrivalMax, _ := strconv.Atoi(line[3])
-		costMin, _ := strconv.Atoi(line[4])
-		costMax, _ := strconv.Atoi(line[5])
-		deadline, _ := strconv.Atoi(line[6])
-		priority, _ := strconv.Atoi(line[7])
+	err := csvFields(filename, v, func(line []string, fieldError func(int, error) error) error {
+		var err error
+		var taskid, jobid uint
+		var times [6]Time
+		if taskid, err = parseID(line, 0, "Task ID", fieldError); err != nil {
+			return err
+		}
+		if jobid, err = parseID(line, 1, "Job ID", fieldError); err != nil {
+			return err
+		}
+		roundings := [...]Rounding{RoundDown, RoundUp, RoundDown, RoundUp, RoundUp}
+		for i, name := range []string{"Arrival min", "Arrival max", "Cost min", "Cost max", "Deadline"} {
+			if times[i], err = parseTime(m, line, i+2, name, roundings[i], fieldError); err != nil {
+				return err
+			}
+		}
+		if times[5], err = parsePriority(m, line, 7, fieldError); err != nil {
+			return err
+		}
 		jobName := "J" + fmt.Sprint(taskid) + "," + fmt.Sprint(jobid)
 
 		jobInstance := &Job{
 			Name:     jobName,
-			TaskID:   uint(taskid),
-			JobID:    uint(jobid),
-			Arrival:  Interval{Start: Time(arrivalMin), End: Time(arrivalMax)},
-			Cost:     Interval{Start: Time(costMin), End: Time(costMax)},
-			Deadline: Time(deadline),
-			Priority: Time(priority),
-		}
-		// fmt.Println(jobInstance.String())
+			TaskID:   taskid,
+			JobID:    jobid,
+			Arrival:  Interval{Start: times[0], End: times[1]},
+			Cost:     Interval{Start: times[2], End: times[3]},
+			Deadline: times[4],
+			Priority: times[5],
+		}
+		if err := jobInstance.Validate(m); err != nil {
+			return fieldError(0, err)
+		}
+		if names[jobName] {
+			return fieldError(0, fmt.Errorf("duplicate job %s", jobName))
+		}
+		names[jobName] = true
+
 		jobs = append(jobs, jobInstance)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return jobs
+	return jobs, nil
 }
 
-func ReadPrecedence(filename string, jobs *JobSet, v *verbose.Logger) {
+func ReadPrecedence(filename string, jobs *JobSet, m TimeModel, v *verbose.Logger) error {
+	return csvFields(filename, v, func(line []string, fieldError func(int, error) error) error {
+		var ids [4]uint
+		for i, name := range []string{"From Task ID", "From Job ID", "To Task ID", "To Job ID"} {
+			var err error
+			if ids[i], err = parseID(line, i, name, fieldError); err != nil {
+				return err
+			}
+		}
+		fromJobName := "J" + fmt.Sprint(ids[0]) + "," + fmt.Sprint(ids[1])
+		toJobName := "J" + fmt.Sprint(ids[2]) + "," + fmt.Sprint(ids[3])
 
-	csvFile, err := os.Open(filename)
-	if err != nil {
-		fmt.Println(err)
-	}
+		if jobs.GetByName(fromJobName) == nil {
+			return fieldError(0, fmt.Errorf("unknown job %s", fromJobName))
+		}
+		toJob := jobs.GetByName(toJobName)
+		if toJob == nil {
+			return fieldError(2, fmt.Errorf("unknown job %s", toJobName))
+		}
 
-	v.Debug("Successfully Opened CSV file")
+		// the delay columns are optional
+		var delay Interval
+		if len(line) >= 6 {
+			var err error
+			if delay.Start, err = parseTime(m, line, 4, "Delay min", RoundDown, fieldError); err != nil {
+				return err
+			}
+			if delay.End, err = parseTime(m, line, 5, "Delay max", RoundUp, fieldError); err != nil {
+				return err
+			}
+			if err := validateDelay(m, delay); err != nil {
+				return fieldError(4, err)
+			}
+		}
+		toJob.AddPredecessorWithDelay(fromJobName, delay)
+		return nil
+	})
+}
 
-	defer csvFile.Close()
+// yamlJobRef refers to a job by its task and job IDs in YAML files.
+type yamlJobRef struct {
+	TaskID uint `yaml:"Task ID"`
+	JobID  uint `yaml:"Job ID"`
+}
 
-	reader := csv.NewReader(csvFile)
-	reader.TrimLeadingSpace = true
+// yamlDelay is the optional delay of a precedence constraint in YAML files.
+type yamlDelay struct {
+	DelayMin timeValue `yaml:"Delay min"`
+	DelayMax timeValue `yaml:"Delay max"`
+}
 
-	// skip first line
-	if _, err := reader.Read(); err != nil {
-		v.Panic(err)
-		panic(err)
-	}
+func (d yamlDelay) interval(p *timeParser) Interval {
+	return p.optionalInterval(d.DelayMin, d.DelayMax, "Delay")
+}
 
-	csvLines, err := reader.ReadAll()
-	if err != nil {
-		v.Error(err)
+func validateDelay(m TimeModel, delay Interval) error {
+	if delay.Start > delay.End || delay.Start < 0 {
+		return fmt.Errorf("invalid delay %s", delay.Format(m))
 	}
+	return nil
+}
 
-	for _, line := range csvLines {
-		fromTaskid, _ := strconv.ParseUint(line[0], 10, 32)
-		fromJobid, _ := strconv.ParseUint(line[1], 10, 32)
-		fromJobName := "J" + fmt.Sprint(fromTaskid) + "," + fmt.Sprint(fromJobid)
-		toTaskid, _ := strconv.ParseUint(line[2], 10, 32)
-		toJobid, _ := strconv.ParseUint(line[3], 10, 32)
-		toJobName := "J" + fmt.Sprint(toTaskid) + "," + fmt.Sprint(toJobid)
-		jobs.GetByName(toJobName).AddPredecessor(fromJobName)
-	}
+func (r yamlJobRef) name() string {
+	return "J" + fmt.Sprint(r.TaskID) + "," + fmt.Sprint(r.JobID)
 }
 
-func ReadJobSetYAML(filename string, v *verbose.Logger) JobSet {
+func ReadJobSetYAML(filename string, m TimeModel, v *verbose.Logger) (JobSet, error) {
 
+	type yamlJob struct {
+		TaskID     uint      `yaml:"Task ID"`
+		JobID      uint      `yaml:"Job ID"`
+		ArrivalMin timeValue `yaml:"Arrival min"`
+		ArrivalMax timeValue `yaml:"Arrival max"`
+		CostMin    timeValue `yaml:"Cost min"`
+		CostMax    timeValue `yaml:"Cost max"`
+		Deadline   timeValue `yaml:"Deadline"`
+		Priority   timeValue `yaml:"Priority"`
+		Segments   []struct {
+			CostMin timeValue `yaml:"Cost min"`
+			CostMax timeValue `yaml:"Cost max"`
+		} `yaml:"Segments"`
+		Suspensions []struct {
+			SuspensionMin timeValue `yaml:"Suspension min"`
+			SuspensionMax timeValue `yaml:"Suspension max"`
+		} `yaml:"Suspensions"`
+		Predecessors []yaml.Node `yaml:"Predecessors"`
+	}
+
+	// the times of the jobs are in the unit of the header, if any
 	type yamlFile struct {
-		Jobset []struct {
-			TaskID     uint `yaml:"Task ID"`
-			JobID      uint `yaml:"Job ID"`
-			ArrivalMin Time `yaml:"Arrival min"`
-			ArrivalMax Time `yaml:"Arrival max"`
-			CostMin    Time `yaml:"Cost min"`
-			CostMax    Time `yaml:"Cost max"`
-			Deadline   Time `yaml:"Deadline"`
-			Priority   Time `yaml:"Priority"`
-		} `yaml:"jobset"`
+		Unit   string      `yaml:"unit"`
+		Jobset []yaml.Node `yaml:"jobset"`
+	}
+
+	// predecessors are checked once all jobs are known
+	type predecessorRef struct {
+		node yaml.Node
+		name string
 	}
+	var predecessors []predecessorRef
 
 	var jobs JobSet
 	jobSetInYaml := yamlFile{}
+	names := make(map[string]bool)
 
-	file, err := ioutil.ReadFile(filename)
+	file, err := readInput(filename)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	v.Debug("Successfully Opened YAML file")
 
-	if err := yaml.Unmarshal([]byte(file), &jobSetInYaml); err != nil {
-		v.Panic(err)
-		panic(err)
+	if err := yaml.Unmarshal(file, &jobSetInYaml); err != nil {
+		return nil, &InputError{File: filename, Err: err}
+	}
+	times, err := newTimeParser(m, jobSetInYaml.Unit)
+	if err != nil {
+		return nil, &InputError{File: filename, Err: err}
 	}
 
-	for _, job := range jobSetInYaml.Jobset {
+	for _, node := range jobSetInYaml.Jobset {
+		nodeError := func(err error) error {
+			return &InputError{File: filename, Line: node.Line, Column: node.Column, Err: err}
+		}
+
+		var job yamlJob
+		if err := node.Decode(&job); err != nil {
+			return nil, nodeError(err)
+		}
+
 		jobInstance := &Job{
 			Name:     "J" + fmt.Sprint(job.TaskID) + "," + fmt.Sprint(job.JobID),
 			TaskID:   job.TaskID,
 			JobID:    job.JobID,
-			Arrival:  Interval{Start: job.ArrivalMin, End: job.ArrivalMax},
-			Cost:     Interval{Start: job.CostMin, End: job.CostMax},
-			Deadline: job.Deadline,
-			Priority: job.Priority,
+			Arrival:  times.interval(job.ArrivalMin, job.ArrivalMax, "Arrival"),
+			Deadline: times.time(job.Deadline, "Deadline", RoundUp),
+			Priority: times.priority(job.Priority),
+		}
+		// the cost of a segmented job is the sum of its segments
+		if len(job.Segments) == 0 {
+			jobInstance.Cost = times.interval(job.CostMin, job.CostMax, "Cost")
+		}
+		for _, segment := range job.Segments {
+			cost := times.interval(segment.CostMin, segment.CostMax, "Cost")
+			jobInstance.Segments = append(jobInstance.Segments, cost)
+			jobInstance.Cost = times.sum(jobInstance.Cost, cost, "Cost")
+		}
+		for _, suspension := range job.Suspensions {
+			jobInstance.Suspensions = append(jobInstance.Suspensions, times.interval(suspension.SuspensionMin, suspension.SuspensionMax, "Suspension"))
+		}
+		if err := times.check(); err != nil {
+			return nil, nodeError(err)
 		}
+		for _, predNode := range job.Predecessors {
+			var pred struct {
+				yamlJobRef `yaml:",inline"`
+				yamlDelay  `yaml:",inline"`
+			}
+			if err := predNode.Decode(&pred); err != nil {
+				return nil, &InputError{File: filename, Line: predNode.Line, Column: predNode.Column, Err: err}
+			}
+			delay := pred.interval(times)
+			if err := times.check(); err != nil {
+				return nil, &InputError{File: filename, Line: predNode.Line, Column: predNode.Column, Err: err}
+			}
+			if err := validateDelay(m, delay); err != nil {
+				return nil, &InputError{File: filename, Line: predNode.Line, Column: predNode.Column, Err: err}
+			}
+			jobInstance.AddPredecessorWithDelay(pred.name(), delay)
+			predecessors = append(predecessors, predecessorRef{node: predNode, name: pred.name()})
+		}
+
+		if err := jobInstance.Validate(m); err != nil {
+			return nil, nodeError(err)
+		}
+		if names[jobInstance.Name] {
+			return nil, nodeError(fmt.Errorf("duplicate job %s", jobInstance.Name))
+		}
+		names[jobInstance.Name] = true
+
 		jobs = append(jobs, jobInstance)
 	}
 
-	return jobs
+	for _, pred := range predecessors {
+		if !names[pred.name] {
+			return nil, &InputError{File: filename, Line: pred.node.Line, Column: pred.node.Column, Err: fmt.Errorf("unknown job %s", pred.name)}
+		}
+	}
+
+	return jobs, nil
+}
+
+// ReadJobSetJSON reads a job set in JSON format. The jobs are listed under
+// "jobset" with the same fields as in YAML files, and the unit of their
+// times is given by "unit", if any.
+func ReadJobSetJSON(filename string, m TimeModel, v *verbose.Logger) (JobSet, error) {
+
+	type jsonJob struct {
+		TaskID     uint      `json:"Task ID"`
+		JobID      uint      `json:"Job ID"`
+		ArrivalMin timeValue `json:"Arrival min"`
+		ArrivalMax timeValue `json:"Arrival max"`
+		CostMin    timeValue `json:"Cost min"`
+		CostMax    timeValue `json:"Cost max"`
+		Deadline   timeValue `json:"Deadline"`
+		Priority   timeValue `json:"Priority"`
+		Segments   []struct {
+			CostMin timeValue `json:"Cost min"`
+			CostMax timeValue `json:"Cost max"`
+		} `json:"Segments"`
+		Suspensions []struct {
+			SuspensionMin timeValue `json:"Suspension min"`
+			SuspensionMax timeValue `json:"Suspension max"`
+		} `json:"Suspensions"`
+		Predecessors []struct {
+			TaskID   uint      `json:"Task ID"`
+			JobID    uint      `json:"Job ID"`
+			DelayMin timeValue `json:"Delay min"`
+			DelayMax timeValue `json:"Delay max"`
+		} `json:"Predecessors"`
+	}
+
+	// predecessors are checked once all jobs are known
+	type predecessorRef struct {
+		offset int64
+		name   string
+	}
+	var predecessors []predecessorRef
+
+	var jobs JobSet
+	names := make(map[string]bool)
+
+	file, err := readInput(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Debug("Successfully Opened JSON file")
+
+	// the unit may follow the jobs, and the syntax errors are located below
+	var header struct {
+		Unit string `json:"unit"`
+	}
+	json.Unmarshal(file, &header)
+	times, err := newTimeParser(m, header.Unit)
+	if err != nil {
+		return nil, &InputError{File: filename, Err: err}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(file))
+	offsetError := func(offset int64, err error) error {
+		var syntaxError *json.SyntaxError
+		var typeError *json.UnmarshalTypeError
+		if errors.As(err, &syntaxError) {
+			// the offset is just after the invalid character
+			offset = syntaxError.Offset - 1
+		} else if errors.As(err, &typeError) {
+			// the offset is relative to the decoded value
+			offset = jsonValueStart(file, offset) + typeError.Offset - 1
+		} else {
+			offset = jsonValueStart(file, offset)
+		}
+		line, column := jsonPosition(file, offset)
+		return &InputError{File: filename, Line: line, Column: column, Err: err}
+	}
+	expect := func(delim json.Delim) error {
+		token, err := decoder.Token()
+		if err != nil {
+			return offsetError(decoder.InputOffset(), err)
+		}
+		if token != delim {
+			return offsetError(decoder.InputOffset()-1, fmt.Errorf("expected %q", delim))
+		}
+		return nil
+	}
+
+	if err := expect('{'); err != nil {
+		return nil, err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, offsetError(decoder.InputOffset(), err)
+		}
+		if key != "jobset" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, offsetError(decoder.InputOffset(), err)
+			}
+			continue
+		}
+
+		if err := expect('['); err != nil {
+			return nil, err
+		}
+		for decoder.More() {
+			offset := decoder.InputOffset()
+			nodeError := func(err error) error {
+				return offsetError(offset, err)
+			}
+
+			var job jsonJob
+			if err := decoder.Decode(&job); err != nil {
+				return nil, nodeError(err)
+			}
+
+			jobInstance := &Job{
+				Name:     "J" + fmt.Sprint(job.TaskID) + "," + fmt.Sprint(job.JobID),
+				TaskID:   job.TaskID,
+				JobID:    job.JobID,
+				Arrival:  times.interval(job.ArrivalMin, job.ArrivalMax, "Arrival"),
+				Deadline: times.time(job.Deadline, "Deadline", RoundUp),
+				Priority: times.priority(job.Priority),
+			}
+			// the cost of a segmented job is the sum of its segments
+			if len(job.Segments) == 0 {
+				jobInstance.Cost = times.interval(job.CostMin, job.CostMax, "Cost")
+			}
+			for _, segment := range job.Segments {
+				cost := times.interval(segment.CostMin, segment.CostMax, "Cost")
+				jobInstance.Segments = append(jobInstance.Segments, cost)
+				jobInstance.Cost = times.sum(jobInstance.Cost, cost, "Cost")
+			}
+			for _, suspension := range job.Suspensions {
+				jobInstance.Suspensions = append(jobInstance.Suspensions, times.interval(suspension.SuspensionMin, suspension.SuspensionMax, "Suspension"))
+			}
+			for _, pred := range job.Predecessors {
+				ref := yamlJobRef{TaskID: pred.TaskID, JobID: pred.JobID}
+				delay := times.optionalInterval(pred.DelayMin, pred.DelayMax, "Delay")
+				if err := times.check(); err != nil {
+					return nil, nodeError(err)
+				}
+				if err := validateDelay(m, delay); err != nil {
+					return nil, nodeError(err)
+				}
+				jobInstance.AddPredecessorWithDelay(ref.name(), delay)
+				predecessors = append(predecessors, predecessorRef{offset: offset, name: ref.name()})
+			}
+			if err := times.check(); err != nil {
+				return nil, nodeError(err)
+			}
+
+			if err := jobInstance.Validate(m); err != nil {
+				return nil, nodeError(err)
+			}
+			if names[jobInstance.Name] {
+				return nil, nodeError(fmt.Errorf("duplicate job %s", jobInstance.Name))
+			}
+			names[jobInstance.Name] = true
+
+			jobs = append(jobs, jobInstance)
+		}
+		if err := expect(']'); err != nil {
+			return nil, err
+		}
+	}
+	if err := expect('}'); err != nil {
+		return nil, err
+	}
+
+	for _, pred := range predecessors {
+		if !names[pred.name] {
+			return nil, offsetError(pred.offset, fmt.Errorf("unknown job %s", pred.name))
+		}
+	}
+
+	return jobs, nil
+}
+
+// jsonValueStart returns the offset of the first value at or after offset
+// in a JSON file.
+func jsonValueStart(file []byte, offset int64) int64 {
+	for offset < int64(len(file)) && strings.IndexByte(" \t\r\n,:", file[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// jsonPosition returns the line and column of offset in a JSON file.
+func jsonPosition(file []byte, offset int64) (int, int) {
+	if offset > int64(len(file)) {
+		offset = int64(len(file))
+	}
+	line, column := 1, 1
+	for _, c := range file[:offset] {
+		if c == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+// ReadPrecedenceYAML reads the precedence constraints listed under
+// "precedence", each with a "From" and a "To" job, into jobs.
+func ReadPrecedenceYAML(filename string, jobs *JobSet, m TimeModel, v *verbose.Logger) error {
+
+	type yamlEdge struct {
+		From      yamlJobRef `yaml:"From"`
+		To        yamlJobRef `yaml:"To"`
+		yamlDelay `yaml:",inline"`
+	}
+
+	type yamlFile struct {
+		Unit       string      `yaml:"unit"`
+		Precedence []yaml.Node `yaml:"precedence"`
+	}
+
+	precedenceInYaml := yamlFile{}
+
+	file, err := readInput(filename)
+	if err != nil {
+		return err
+	}
+
+	v.Debug("Successfully Opened YAML file")
+
+	if err := yaml.Unmarshal(file, &precedenceInYaml); err != nil {
+		return &InputError{File: filename, Err: err}
+	}
+	times, err := newTimeParser(m, precedenceInYaml.Unit)
+	if err != nil {
+		return &InputError{File: filename, Err: err}
+	}
+
+	for _, node := range precedenceInYaml.Precedence {
+		nodeError := func(err error) error {
+			return &InputError{File: filename, Line: node.Line, Column: node.Column, Err: err}
+		}
+
+		var e yamlEdge
+		if err := node.Decode(&e); err != nil {
+			return nodeError(err)
+		}
+		if jobs.GetByName(e.From.name()) == nil {
+			return nodeError(fmt.Errorf("unknown job %s", e.From.name()))
+		}
+		toJob := jobs.GetByName(e.To.name())
+		if toJob == nil {
+			return nodeError(fmt.Errorf("unknown job %s", e.To.name()))
+		}
+		delay := e.interval(times)
+		if err := times.check(); err != nil {
+			return nodeError(err)
+		}
+		if err := validateDelay(m, delay); err != nil {
+			return nodeError(err)
+		}
+		toJob.AddPredecessorWithDelay(e.From.name(), delay)
+	}
+
+	return nil
+}
+
+// ReadTaskSet reads a task set in CSV format, one task per line.
+func ReadTaskSet(filename string, m TimeModel, v *verbose.Logger) (TaskSet, error) {
+	var tasks TaskSet
+	ids := make(map[uint]bool)
+
+	err := csvFields(filename, v, func(line []string, fieldError func(int, error) error) error {
+		taskid, err := parseID(line, 0, "Task ID", fieldError)
+		if err != nil {
+			return err
+		}
+		var times [7]Time
+		roundings := [...]Rounding{Exact, Exact, RoundUp, RoundDown, RoundUp, RoundUp}
+		for i, name := range []string{"Period", "Offset", "Jitter", "BCET", "WCET", "Deadline"} {
+			if times[i], err = parseTime(m, line, i+1, name, roundings[i], fieldError); err != nil {
+				return err
+			}
+		}
+		if times[6], err = parsePriority(m, line, 7, fieldError); err != nil {
+			return err
+		}
+
+		task := &Task{
+			TaskID:   taskid,
+			Period:   times[0],
+			Offset:   times[1],
+			Jitter:   times[2],
+			Cost:     Interval{Start: times[3], End: times[4]},
+			Deadline: times[5],
+			Priority: times[6],
+		}
+		if err := task.Validate(m); err != nil {
+			return fieldError(0, err)
+		}
+		if ids[taskid] {
+			return fieldError(0, fmt.Errorf("duplicate task %d", taskid))
+		}
+		ids[taskid] = true
+
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// ReadTaskSetYAML reads a task set in YAML format. Independent tasks are
+// listed under "taskset" and DAG tasks under "dagtasks". Each DAG task gives
+// the release parameters shared by its subtasks, the subtasks with their
+// BCET and WCET, and the edges between them.
+func ReadTaskSetYAML(filename string, m TimeModel, v *verbose.Logger) (TaskSet, error) {
+
+	type yamlTask struct {
+		TaskID   uint      `yaml:"Task ID"`
+		Period   timeValue `yaml:"Period"`
+		Offset   timeValue `yaml:"Offset"`
+		Jitter   timeValue `yaml:"Jitter"`
+		BCET     timeValue `yaml:"BCET"`
+		WCET     timeValue `yaml:"WCET"`
+		Deadline timeValue `yaml:"Deadline"`
+		Priority timeValue `yaml:"Priority"`
+	}
+
+	type yamlDAGTask struct {
+		Period   timeValue `yaml:"Period"`
+		Offset   timeValue `yaml:"Offset"`
+		Jitter   timeValue `yaml:"Jitter"`
+		Deadline timeValue `yaml:"Deadline"`
+		Priority timeValue `yaml:"Priority"`
+		Subtasks []struct {
+			TaskID uint      `yaml:"Task ID"`
+			BCET   timeValue `yaml:"BCET"`
+			WCET   timeValue `yaml:"WCET"`
+		} `yaml:"Subtasks"`
+		Edges []yaml.Node `yaml:"Edges"`
+	}
+
+	type yamlEdge struct {
+		From uint `yaml:"From"`
+		To   uint `yaml:"To"`
+	}
+
+	// the times of the tasks are in the unit of the header, if any
+	type yamlFile struct {
+		Unit     string      `yaml:"unit"`
+		Taskset  []yaml.Node `yaml:"taskset"`
+		DAGTasks []yaml.Node `yaml:"dagtasks"`
+	}
+
+	var tasks TaskSet
+	taskSetInYaml := yamlFile{}
+	ids := make(map[uint]bool)
+
+	file, err := readInput(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Debug("Successfully Opened YAML file")
+
+	if err := yaml.Unmarshal(file, &taskSetInYaml); err != nil {
+		return nil, &InputError{File: filename, Err: err}
+	}
+	times, err := newTimeParser(m, taskSetInYaml.Unit)
+	if err != nil {
+		return nil, &InputError{File: filename, Err: err}
+	}
+
+	nodeError := func(node yaml.Node, err error) error {
+		return &InputError{File: filename, Line: node.Line, Column: node.Column, Err: err}
+	}
+	addTask := func(node yaml.Node, task *Task) error {
+		if err := times.check(); err != nil {
+			return nodeError(node, err)
+		}
+		if err := task.Validate(m); err != nil {
+			return nodeError(node, err)
+		}
+		if ids[task.TaskID] {
+			return nodeError(node, fmt.Errorf("duplicate task %d", task.TaskID))
+		}
+		ids[task.TaskID] = true
+
+		tasks = append(tasks, task)
+		return nil
+	}
+
+	for _, node := range taskSetInYaml.Taskset {
+		var t yamlTask
+		if err := node.Decode(&t); err != nil {
+			return nil, nodeError(node, err)
+		}
+
+		task := &Task{
+			TaskID:   t.TaskID,
+			Period:   times.time(t.Period, "Period", Exact),
+			Offset:   times.optionalTime(t.Offset, "Offset", Exact),
+			Jitter:   times.optionalTime(t.Jitter, "Jitter", RoundUp),
+			Cost:     Interval{Start: times.time(t.BCET, "BCET", RoundDown), End: times.time(t.WCET, "WCET", RoundUp)},
+			Deadline: times.time(t.Deadline, "Deadline", RoundUp),
+			Priority: times.priority(t.Priority),
+		}
+		if err := addTask(node, task); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, node := range taskSetInYaml.DAGTasks {
+		var d yamlDAGTask
+		if err := node.Decode(&d); err != nil {
+			return nil, nodeError(node, err)
+		}
+		if len(d.Subtasks) == 0 {
+			return nil, nodeError(node, errors.New("DAG task without subtasks"))
+		}
+
+		period := times.time(d.Period, "Period", Exact)
+		offset := times.optionalTime(d.Offset, "Offset", Exact)
+		jitter := times.optionalTime(d.Jitter, "Jitter", RoundUp)
+		deadline := times.time(d.Deadline, "Deadline", RoundUp)
+		priority := times.priority(d.Priority)
+
+		subtasks := make(map[uint]*Task, len(d.Subtasks))
+		for _, st := range d.Subtasks {
+			task := &Task{
+				TaskID:   st.TaskID,
+				Period:   period,
+				Offset:   offset,
+				Jitter:   jitter,
+				Cost:     Interval{Start: times.time(st.BCET, "BCET", RoundDown), End: times.time(st.WCET, "WCET", RoundUp)},
+				Deadline: deadline,
+				Priority: priority,
+			}
+			if err := addTask(node, task); err != nil {
+				return nil, err
+			}
+			subtasks[st.TaskID] = task
+		}
+
+		for _, edgeNode := range d.Edges {
+			var e yamlEdge
+			if err := edgeNode.Decode(&e); err != nil {
+				return nil, nodeError(edgeNode, err)
+			}
+			if subtasks[e.From] == nil || subtasks[e.To] == nil {
+				return nil, nodeError(edgeNode, fmt.Errorf("edge %d -> %d between tasks that are not subtasks of this DAG task", e.From, e.To))
+			}
+			subtasks[e.To].Predecessors = append(subtasks[e.To].Predecessors, e.From)
+		}
+	}
+
+	return tasks, nil
 }
@@ -0,0 +1,339 @@
+package comm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lfkeitel/verbose"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const jobSetHeader = "Task ID,Job ID,Arrival min,Arrival max,Cost min,Cost max,Deadline,Priority\n"
+
+// yamlJob and jsonJob start a job set with job J1,1, whose other fields
+// follow.
+const (
+	yamlJob = "jobset:\n  - Task ID: 1\n    Job ID: 1\n"
+	jsonJob = "{\"jobset\": [\n  {\"Task ID\": 1, \"Job ID\": 1, "
+)
+
+// writeInput writes content to the file name in a temporary directory and
+// returns its path.
+func writeInput(t *testing.T, name, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+// checkInputError checks that err is an InputError at line:column whose
+// message contains want.
+func checkInputError(t *testing.T, err error, line, column int, want string) {
+	t.Helper()
+	var inputError *InputError
+	if !errors.As(err, &inputError) {
+		t.Fatalf("error %v, want an input error", err)
+	}
+	if inputError.Line != line || inputError.Column != column {
+		t.Errorf("error at %d:%d, want %d:%d (%v)", inputError.Line, inputError.Column, line, column, err)
+	}
+	if !strings.Contains(inputError.Err.Error(), want) {
+		t.Errorf("error %q does not contain %q", inputError.Err, want)
+	}
+}
+
+func TestReadJobSetErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		line, column int
+		want         string
+	}{
+		{name: "empty file", content: "", want: "missing header line"},
+		{name: "invalid ID", content: jobSetHeader + "1,1,0,0,1,2,10,1\n1,x,0,0,1,2,10,1\n", line: 3, column: 3, want: `invalid Job ID "x"`},
+		{name: "invalid time", content: jobSetHeader + "1,1,0,0,1,2,abc,1\n", line: 2, column: 13, want: "Deadline"},
+		{name: "invalid priority", content: jobSetHeader + "1, 1, 0, 0, 1, 2, 10, 1.5\n", line: 2, column: 23, want: "priorities are integers"},
+		{name: "missing fields", content: jobSetHeader + "1,1,0,0,1,2\n", line: 2, column: 1, want: "wrong number of fields"},
+		{name: "inverted arrival", content: jobSetHeader + "1,1,5,3,1,2,10,1\n", line: 2, column: 1, want: "earliest arrival 5 is after latest arrival 3"},
+		{name: "zero cost", content: jobSetHeader + "1,1,0,0,0,0,10,1\n", line: 2, column: 1, want: "worst-case cost must be positive"},
+		{name: "duplicate job", content: jobSetHeader + "1,1,0,0,1,2,10,1\n1,1,0,0,1,2,10,1\n", line: 3, column: 1, want: "duplicate job J1,1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadJobSet(writeInput(t, "jobs.csv", tt.content), TimeModel{}, verbose.New("test"))
+			checkInputError(t, err, tt.line, tt.column, tt.want)
+		})
+	}
+}
+
+func TestReadJobSet(t *testing.T) {
+	jobs, err := ReadJobSet(writeInput(t, "jobs.csv", jobSetHeader+"1, 2, 0, 3, 1, 2, 10, 4\n"), TimeModel{}, verbose.New("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Job{Name: "J1,2", TaskID: 1, JobID: 2, Arrival: Interval{Start: 0, End: 3}, Cost: Interval{Start: 1, End: 2}, Deadline: 10, Priority: 4}
+	if len(jobs) != 1 || jobs[0].Name != want.Name || jobs[0].Arrival != want.Arrival || jobs[0].Cost != want.Cost || jobs[0].Deadline != want.Deadline || jobs[0].Priority != want.Priority {
+		t.Errorf("jobs %v, want %v", jobs, want)
+	}
+}
+
+// TestReadJobSetMissingFields checks that the required fields of the jobs
+// of yaml and json job sets are reported at the job that lacks them, while
+// the optional fields default to 0.
+func TestReadJobSetMissingFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		line, column int
+		want         string
+	}{
+		{name: "yaml arrival", file: "jobs.yaml", content: yamlJob + "    Arrival max: 0\n    Cost min: 1\n    Cost max: 2\n    Deadline: 10\n    Priority: 1\n", line: 2, column: 5, want: "missing Arrival min"},
+		{name: "yaml cost", file: "jobs.yaml", content: yamlJob + "    Arrival min: 0\n    Arrival max: 0\n    Cost min: 1\n    Deadline: 10\n    Priority: 1\n", line: 2, column: 5, want: "missing Cost max"},
+		{name: "yaml deadline", file: "jobs.yaml", content: yamlJob + "    Arrival min: 0\n    Arrival max: 0\n    Cost min: 1\n    Cost max: 2\n    Priority: 1\n", line: 2, column: 5, want: "missing Deadline"},
+		{name: "yaml priority", file: "jobs.yaml", content: yamlJob + "    Arrival min: 0\n    Arrival max: 0\n    Cost min: 1\n    Cost max: 2\n    Deadline: 10\n", line: 2, column: 5, want: "missing Priority"},
+		{name: "yaml segment cost", file: "jobs.yaml", content: yamlJob + "    Arrival min: 0\n    Arrival max: 0\n    Deadline: 10\n    Priority: 1\n    Segments: [{Cost min: 1}]\n", line: 2, column: 5, want: "missing Cost max"},
+		{name: "json deadline", file: "jobs.json", content: jsonJob + "\"Arrival min\": 0, \"Arrival max\": 0, \"Cost min\": 1, \"Cost max\": 2, \"Priority\": 1}\n]}\n", line: 2, column: 3, want: "missing Deadline"},
+		{name: "json null priority", file: "jobs.json", content: jsonJob + "\"Arrival min\": 0, \"Arrival max\": 0, \"Cost min\": 1, \"Cost max\": 2, \"Deadline\": 10, \"Priority\": null}\n]}\n", line: 2, column: 3, want: "missing Priority"},
+		{name: "yaml segments and delay", file: "jobs.yaml", content: yamlJob + "    Arrival min: 0\n    Arrival max: 0\n    Deadline: 10\n    Priority: 1\n    Segments: [{Cost min: 1, Cost max: 2}]\n" +
+			"  - {Task ID: 1, Job ID: 2, Arrival min: 0, Arrival max: 0, Cost min: 1, Cost max: 2, Deadline: 10, Priority: 2, Predecessors: [{Task ID: 1, Job ID: 1}]}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read := ReadJobSetYAML
+			if strings.HasSuffix(tt.file, ".json") {
+				read = ReadJobSetJSON
+			}
+			jobs, err := read(writeInput(t, tt.file, tt.content), TimeModel{}, verbose.New("test"))
+			if tt.want != "" {
+				checkInputError(t, err, tt.line, tt.column, tt.want)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := jobs.GetByName("J1,1").Cost; got != (Interval{Start: 1, End: 2}) {
+				t.Errorf("cost %v, want the cost of the segment", got)
+			}
+			if got := jobs.GetByName("J1,2").GetPredecessorDelay("J1,1"); got != (Interval{}) {
+				t.Errorf("delay %v, want none", got)
+			}
+		})
+	}
+}
+
+// twoJobs is a job set of two jobs J1,1 and J1,2 for the precedence tests.
+const twoJobs = jobSetHeader + "1,1,0,0,1,2,10,1\n1,2,0,0,1,2,10,2\n"
+
+// TestReadJobSetSegmentCost checks that the cost of a segmented job, the sum
+// of its segments, is reported at the job if it is out of range.
+func TestReadJobSetSegmentCost(t *testing.T) {
+	segments := fmt.Sprintf(`[{"Cost min": 1, "Cost max": %d}, {"Cost min": 1, "Cost max": 1}]`, MaxTime)
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		line, column int
+	}{
+		{name: "yaml", file: "jobs.yaml", content: yamlJob + "    Arrival min: 0\n    Arrival max: 0\n    Deadline: 10\n    Priority: 1\n    Segments: " + segments + "\n", line: 2, column: 5},
+		{name: "json", file: "jobs.json", content: jsonJob + `"Arrival min": 0, "Arrival max": 0, "Deadline": 10, "Priority": 1, "Segments": ` + segments + "}\n]}\n", line: 2, column: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read := ReadJobSetYAML
+			if tt.file == "jobs.json" {
+				read = ReadJobSetJSON
+			}
+			_, err := read(writeInput(t, tt.file, tt.content), TimeModel{}, verbose.New("test"))
+			checkInputError(t, err, tt.line, tt.column, "Cost: time")
+		})
+	}
+}
+
+func TestReadJobSetYAMLPredecessors(t *testing.T) {
+	const job1 = "jobset:\n  - {Task ID: 1, Job ID: 1, Arrival min: 0, Arrival max: 0, Cost min: 1, Cost max: 2, Deadline: 10, Priority: 1}\n"
+	tests := []struct {
+		name         string
+		content      string
+		want         []string
+		line, column int
+		err          string
+	}{
+		{
+			name:    "predecessor",
+			content: job1 + "  - {Task ID: 1, Job ID: 2, Arrival min: 0, Arrival max: 0, Cost min: 1, Cost max: 2, Deadline: 10, Priority: 2,\n     Predecessors: [{Task ID: 1, Job ID: 1}]}\n",
+			want:    []string{"J1,1"},
+		},
+		{
+			name:    "predecessor listed later",
+			content: "jobset:\n  - {Task ID: 1, Job ID: 2, Arrival min: 0, Arrival max: 0, Cost min: 1, Cost max: 2, Deadline: 10, Priority: 2,\n     Predecessors: [{Task ID: 1, Job ID: 1}]}\n" + job1[len("jobset:\n"):],
+			want:    []string{"J1,1"},
+		},
+		{
+			name:    "unknown predecessor",
+			content: job1 + "  - Task ID: 1\n    Job ID: 2\n    Arrival min: 0\n    Arrival max: 0\n    Cost min: 1\n    Cost max: 2\n    Deadline: 10\n    Priority: 2\n    Predecessors:\n      - {Task ID: 1, Job ID: 3}\n",
+			line:    12,
+			column:  9,
+			err:     "unknown job J1,3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := ReadJobSetYAML(writeInput(t, "jobs.yaml", tt.content), TimeModel{}, verbose.New("test"))
+			if tt.err != "" {
+				checkInputError(t, err, tt.line, tt.column, tt.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := jobs.GetByName("J1,2").Predecessors; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("predecessors %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPrecedenceErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		line, column int
+		want         string
+	}{
+		{name: "csv unknown predecessor", file: "prec.csv", content: "From TID, From JID, To TID, To JID\n1, 1, 1, 2\n1, 3, 1, 2\n", line: 3, column: 1, want: "unknown job J1,3"},
+		{name: "csv unknown successor", file: "prec.csv", content: "From TID, From JID, To TID, To JID\n1, 1, 1, 3\n", line: 2, column: 7, want: "unknown job J1,3"},
+		{name: "csv invalid ID", file: "prec.csv", content: "From TID, From JID, To TID, To JID\n1, 1, 1, -2\n", line: 2, column: 10, want: `invalid To Job ID "-2"`},
+		{name: "yaml unknown predecessor", file: "prec.yaml", content: "precedence:\n  - From: {Task ID: 1, Job ID: 1}\n    To: {Task ID: 1, Job ID: 2}\n  - From: {Task ID: 1, Job ID: 3}\n    To: {Task ID: 1, Job ID: 2}\n", line: 4, column: 5, want: "unknown job J1,3"},
+		{name: "yaml unknown successor", file: "prec.yaml", content: "precedence:\n  - From: {Task ID: 1, Job ID: 1}\n    To: {Task ID: 1, Job ID: 3}\n", line: 2, column: 5, want: "unknown job J1,3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := verbose.New("test")
+			jobs, err := ReadJobSet(writeInput(t, "jobs.csv", twoJobs), TimeModel{}, logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filename := writeInput(t, tt.file, tt.content)
+			if strings.HasSuffix(tt.file, ".yaml") {
+				err = ReadPrecedenceYAML(filename, &jobs, TimeModel{}, logger)
+			} else {
+				err = ReadPrecedence(filename, &jobs, TimeModel{}, logger)
+			}
+			checkInputError(t, err, tt.line, tt.column, tt.want)
+		})
+	}
+}
+
+func TestReadPrecedenceDelays(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		want         Interval
+		line, column int
+		err          string
+	}{
+		{name: "csv without delay", file: "prec.csv", content: "From TID, From JID, To TID, To JID\n1, 1, 1, 2\n", want: Interval{}},
+		{name: "csv delay", file: "prec.csv", content: "From TID, From JID, To TID, To JID, Delay min, Delay max\n1, 1, 1, 2, 1, 3\n", want: Interval{Start: 1, End: 3}},
+		{name: "csv delay with unit", file: "prec.csv", content: "From TID, From JID, To TID, To JID, Delay min, Delay max\n1, 1, 1, 2, 1500ns, 2us\n", want: Interval{Start: 1500, End: 2000}},
+		{name: "csv inverted delay", file: "prec.csv", content: "From TID, From JID, To TID, To JID, Delay min, Delay max\n1, 1, 1, 2, 3, 1\n", line: 2, column: 13, err: "invalid delay"},
+		{name: "csv negative delay", file: "prec.csv", content: "From TID, From JID, To TID, To JID, Delay min, Delay max\n1, 1, 1, 2, -1, 1\n", line: 2, column: 13, err: "invalid delay"},
+		{name: "yaml delay", file: "prec.yaml", content: "precedence:\n  - From: {Task ID: 1, Job ID: 1}\n    To: {Task ID: 1, Job ID: 2}\n    Delay min: 1\n    Delay max: 3\n", want: Interval{Start: 1, End: 3}},
+		{name: "yaml inverted delay", file: "prec.yaml", content: "precedence:\n  - From: {Task ID: 1, Job ID: 1}\n    To: {Task ID: 1, Job ID: 2}\n    Delay min: 3\n    Delay max: 1\n", line: 2, column: 5, err: "invalid delay"},
+		{name: "yaml invalid delay", file: "prec.yaml", content: "precedence:\n  - From: {Task ID: 1, Job ID: 1}\n    To: {Task ID: 1, Job ID: 2}\n    Delay min: soon\n    Delay max: 1\n", line: 2, column: 5, err: "Delay"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := verbose.New("test")
+			jobs, err := ReadJobSet(writeInput(t, "jobs.csv", twoJobs), TimeModel{}, logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filename := writeInput(t, tt.file, tt.content)
+			if strings.HasSuffix(tt.file, ".yaml") {
+				err = ReadPrecedenceYAML(filename, &jobs, TimeModel{}, logger)
+			} else {
+				err = ReadPrecedence(filename, &jobs, TimeModel{}, logger)
+			}
+			if tt.err != "" {
+				checkInputError(t, err, tt.line, tt.column, tt.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := jobs.GetByName("J1,2").PredecessorDelays["J1,1"]; got != tt.want {
+				t.Errorf("delay %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestJobSetFormats checks that the csv, yaml and json versions of the same
+// job set are read alike.
+func TestJobSetFormats(t *testing.T) {
+	logger := verbose.New("test")
+	want, err := ReadJobSet("../../example/example3.csv", TimeModel{}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"example3.yaml", "example3.json"} {
+		var jobs JobSet
+		if strings.HasSuffix(file, ".yaml") {
+			jobs, err = ReadJobSetYAML("../../example/"+file, TimeModel{}, logger)
+		} else {
+			jobs, err = ReadJobSetJSON("../../example/"+file, TimeModel{}, logger)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(jobs, want) {
+			t.Errorf("%s: jobs %v, want %v", file, jobs, want)
+		}
+	}
+}
+
+func TestReadJobSetJSONErrors(t *testing.T) {
+	const job1 = `{"Task ID": 1, "Job ID": 1, "Arrival min": 0, "Arrival max": 0, "Cost min": 1, "Cost max": 2, "Deadline": 10, "Priority": 1}`
+	tests := []struct {
+		name         string
+		content      string
+		line, column int
+		want         string
+	}{
+		{name: "not an object", content: "[]\n", line: 1, column: 1, want: `expected "{"`},
+		{name: "syntax error", content: "{\"jobset\": [\n  " + job1 + "\n  " + job1 + "\n]}\n", line: 3, column: 3, want: "invalid character"},
+		// type errors are located at the end of the value
+		{name: "type error", content: "{\"jobset\": [\n  " + job1 + ",\n  {\"Task ID\": \"one\"}\n]}\n", line: 3, column: 19, want: "Task ID"},
+		{name: "invalid time", content: "{\"jobset\": [\n  {\"Task ID\": 1, \"Job ID\": 1, \"Arrival min\": 0, \"Arrival max\": 0, \"Cost min\": 1, \"Cost max\": 2, \"Deadline\": \"soon\", \"Priority\": 1}\n]}\n", line: 2, column: 3, want: "Deadline"},
+		{name: "invalid job", content: "{\"jobset\": [\n  " + job1 + ",\n  {\"Task ID\": 1, \"Job ID\": 2, \"Arrival min\": 5, \"Arrival max\": 3, \"Cost min\": 1, \"Cost max\": 2, \"Deadline\": 10, \"Priority\": 1}\n]}\n", line: 3, column: 3, want: "earliest arrival 5 is after latest arrival 3"},
+		{name: "duplicate job", content: "{\"jobset\": [\n  " + job1 + ",\n  " + job1 + "\n]}\n", line: 3, column: 3, want: "duplicate job J1,1"},
+		{name: "unknown predecessor", content: "{\"jobset\": [\n  {\"Task ID\": 1, \"Job ID\": 2, \"Arrival min\": 0, \"Arrival max\": 0, \"Cost min\": 1, \"Cost max\": 2, \"Deadline\": 10, \"Priority\": 1, \"Predecessors\": [{\"Task ID\": 1, \"Job ID\": 3}]}\n]}\n", line: 2, column: 3, want: "unknown job J1,3"},
+		{name: "invalid unit", content: "{\"unit\": \"h\", \"jobset\": []}\n", want: "h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadJobSetJSON(writeInput(t, "jobs.json", tt.content), TimeModel{}, verbose.New("test"))
+			checkInputError(t, err, tt.line, tt.column, tt.want)
+		})
+	}
+}
+
+func TestReadJobSetJSONUnit(t *testing.T) {
+	content := `{"unit": "us", "jobset": [{"Task ID": 1, "Job ID": 1, "Arrival min": 0, "Arrival max": 1.5, "Cost min": 1, "Cost max": 2, "Deadline": 10, "Priority": 1}]}`
+	jobs, err := ReadJobSetJSON(writeInput(t, "jobs.json", content), TimeModel{Resolution: Microsecond}, verbose.New("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the latest arrival is rounded up to the resolution
+	if got, want := jobs[0].Arrival, (Interval{Start: 0, End: 2}); got != want {
+		t.Errorf("arrival %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,19 @@
+package comm
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statistics summarises the size and cost of an exploration.
+type Statistics struct {
+	NumberOfStates uint
+	NumberOfEdges  uint
+	// Depth is the number of exploration steps that were completed.
+	Depth   uint
+	CPUTime time.Duration
+}
+
+func (st Statistics) String() string {
+	return fmt.Sprintf("states: %d, edges: %d, depth: %d, time: %v", st.NumberOfStates, st.NumberOfEdges, st.Depth, st.CPUTime)
+}
@@ -0,0 +1,155 @@
+package comm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Task is a periodic or sporadic task. Its jobs are released every Period
+// time units from Offset on, with up to Jitter time units of release
+// jitter, and must complete within Deadline time units of their release.
+type Task struct {
+	TaskID   uint
+	Period   Time
+	Offset   Time
+	Jitter   Time
+	Cost     Interval
+	Deadline Time
+	Priority Time
+	// Predecessors are the IDs of the tasks whose k-th job precedes the
+	// k-th job of this task, e.g., the subtasks of a DAG task.
+	Predecessors []uint
+}
+
+type TaskSet []*Task
+
+func (t Task) String() string {
+	return "T" + fmt.Sprint(t.TaskID) + "\t" + t.Period.String() + "\t" + t.Offset.String() + "\t" + t.Jitter.String() + "\t" + t.Cost.String() + "\t" + t.Deadline.String() + "\t" + t.Priority.String()
+}
+
+// Validate checks that the parameters of task t are consistent. The times of
+// the error are formatted in the time model m.
+func (t Task) Validate(m TimeModel) error {
+	if t.Period <= 0 {
+		return fmt.Errorf("task %d: the period must be positive", t.TaskID)
+	}
+	if t.Offset < 0 || t.Jitter < 0 {
+		return fmt.Errorf("task %d: negative offset or jitter", t.TaskID)
+	}
+	if t.Cost.Start > t.Cost.End {
+		return fmt.Errorf("task %d: BCET %s exceeds WCET %s", t.TaskID, t.Cost.Start.Format(m), t.Cost.End.Format(m))
+	}
+	if t.Cost.Start < 0 || t.Cost.End <= 0 {
+		return fmt.Errorf("task %d: the WCET must be positive and the BCET non-negative", t.TaskID)
+	}
+	if t.Deadline <= 0 {
+		return fmt.Errorf("task %d: the relative deadline must be positive", t.TaskID)
+	}
+	if err := checkTimeRange(t.Period, t.Offset, t.Jitter, t.Cost.End, t.Deadline); err != nil {
+		return fmt.Errorf("task %d: %v", t.TaskID, err)
+	}
+	return nil
+}
+
+// Validate checks the tasks of T, the uniqueness of their IDs and their
+// precedence constraints, which must refer to tasks of T with the same
+// period and offset.
+func (T TaskSet) Validate(m TimeModel) error {
+	byID := make(map[uint]*Task, len(T))
+	for _, t := range T {
+		if err := t.Validate(m); err != nil {
+			return err
+		}
+		if _, exists := byID[t.TaskID]; exists {
+			return fmt.Errorf("duplicate task %d", t.TaskID)
+		}
+		byID[t.TaskID] = t
+	}
+
+	for _, t := range T {
+		for _, id := range t.Predecessors {
+			pred, exists := byID[id]
+			if !exists {
+				return fmt.Errorf("task %d: unknown predecessor %d", t.TaskID, id)
+			}
+			if pred.Period != t.Period || pred.Offset != t.Offset {
+				return fmt.Errorf("task %d: predecessor %d has a different period or offset", t.TaskID, id)
+			}
+		}
+	}
+	return nil
+}
+
+// Hyperperiod returns the least common multiple of the periods of T.
+func (T TaskSet) Hyperperiod() (Time, error) {
+	if len(T) == 0 {
+		return 0, errors.New("empty task set")
+	}
+
+	h := int64(1)
+	for _, t := range T {
+		p := int64(t.Period)
+		q := h / gcd(h, p)
+		if q > int64(MaxTime)/p {
+			return 0, errors.New("the hyperperiod is too large")
+		}
+		h = q * p
+	}
+	return Time(h), nil
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Expand returns the jobs of T released before horizon. Job k of task i,
+// counted from 1, is named "Ji,k" and is preceded by the k-th jobs of the
+// predecessors of task i. If horizon is 0, the jobs of the first
+// hyperperiod after the largest offset are expanded. The times of T are in
+// the time model m.
+func (T TaskSet) Expand(horizon Time, m TimeModel) (JobSet, error) {
+	if err := T.Validate(m); err != nil {
+		return nil, err
+	}
+	if err := checkTimeRange(horizon); err != nil {
+		return nil, fmt.Errorf("horizon: %v", err)
+	}
+
+	if horizon == 0 {
+		h, err := T.Hyperperiod()
+		if err != nil {
+			return nil, err
+		}
+		horizon = h
+		for _, t := range T {
+			horizon = Maximum(horizon, t.Offset+h)
+		}
+	}
+
+	var jobs JobSet
+	for _, t := range T {
+		for k := 0; ; k++ {
+			release := t.Offset + Time(k)*t.Period
+			if release >= horizon {
+				break
+			}
+			job := &Job{
+				Name:     "J" + fmt.Sprint(t.TaskID) + "," + fmt.Sprint(k+1),
+				TaskID:   t.TaskID,
+				JobID:    uint(k + 1),
+				Arrival:  Interval{Start: release, End: release + t.Jitter},
+				Cost:     t.Cost,
+				Deadline: release + t.Deadline,
+				Priority: t.Priority,
+			}
+			for _, id := range t.Predecessors {
+				job.AddPredecessor("J" + fmt.Sprint(id) + "," + fmt.Sprint(k+1))
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
@@ -0,0 +1,182 @@
+package comm
+
+import (
+	"github.com/lfkeitel/verbose"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHyperperiod(t *testing.T) {
+	tests := []struct {
+		periods []Time
+		want    Time
+		err     bool
+	}{
+		{periods: []Time{10}, want: 10},
+		{periods: []Time{10, 15, 4}, want: 60},
+		{periods: []Time{7, 7}, want: 7},
+		{periods: nil, err: true},
+		{periods: []Time{MaxTime, MaxTime - 1}, err: true},
+	}
+	for _, tt := range tests {
+		var tasks TaskSet
+		for i, p := range tt.periods {
+			tasks = append(tasks, &Task{TaskID: uint(i + 1), Period: p})
+		}
+		got, err := tasks.Hyperperiod()
+		if (err != nil) != tt.err {
+			t.Errorf("Hyperperiod(%v): error %v, want error %v", tt.periods, err, tt.err)
+		} else if err == nil && got != tt.want {
+			t.Errorf("Hyperperiod(%v) = %d, want %d", tt.periods, got, tt.want)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	task := func(id uint, period, offset Time, predecessors ...uint) *Task {
+		return &Task{TaskID: id, Period: period, Offset: offset, Jitter: 1, Cost: Interval{Start: 1, End: 2}, Deadline: period, Priority: Time(id), Predecessors: predecessors}
+	}
+	tests := []struct {
+		name    string
+		tasks   TaskSet
+		horizon Time
+		want    []string
+		err     string
+	}{
+		{name: "hyperperiod", tasks: TaskSet{task(1, 10, 0), task(2, 20, 0)}, want: []string{"J1,1@0", "J1,2@10", "J2,1@0"}},
+		{name: "offset", tasks: TaskSet{task(1, 10, 5), task(2, 20, 0)}, want: []string{"J1,1@5", "J1,2@15", "J2,1@0", "J2,2@20"}},
+		{name: "horizon", tasks: TaskSet{task(1, 10, 0)}, horizon: 25, want: []string{"J1,1@0", "J1,2@10", "J1,3@20"}},
+		{name: "precedence", tasks: TaskSet{task(1, 10, 0), task(2, 10, 0, 1)}, want: []string{"J1,1@0", "J2,1@0<J1,1"}},
+		{name: "horizon out of range", tasks: TaskSet{task(1, 10, 0)}, horizon: MaxTime + 1, err: "horizon"},
+		{name: "zero period", tasks: TaskSet{task(1, 0, 0)}, err: "period must be positive"},
+		{name: "duplicate task", tasks: TaskSet{task(1, 10, 0), task(1, 10, 0)}, err: "duplicate task 1"},
+		{name: "unknown predecessor", tasks: TaskSet{task(1, 10, 0, 2)}, err: "unknown predecessor 2"},
+		{name: "predecessor with another period", tasks: TaskSet{task(1, 10, 0), task(2, 20, 0, 1)}, err: "different period or offset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs, err := tt.tasks.Expand(tt.horizon, TimeModel{})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, j := range jobs {
+				s := j.Name + "@" + j.Arrival.Start.String()
+				for _, pred := range j.Predecessors {
+					s += "<" + pred
+				}
+				// the tasks have a jitter of 1 and an implicit deadline
+				if j.Arrival.End != j.Arrival.Start+1 || j.Deadline != j.Arrival.Start+tt.tasks[j.TaskID-1].Period {
+					t.Errorf("%s: arrival %v, deadline %v", j.Name, j.Arrival, j.Deadline)
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jobs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTaskSetErrors(t *testing.T) {
+	const header = "Task ID, Period, Offset, Jitter, BCET, WCET, Deadline, Priority\n"
+	tests := []struct {
+		name         string
+		content      string
+		line, column int
+		want         string
+	}{
+		{name: "invalid period", content: header + "1, ten, 0, 1, 1, 2, 10, 1\n", line: 2, column: 4, want: "Period"},
+		{name: "inexact offset", content: header + "1, 10, 2.5, 1, 1, 2, 10, 1\n", line: 2, column: 8, want: "Offset"},
+		{name: "inverted cost", content: header + "1, 10, 0, 1, 3, 2, 10, 1\n", line: 2, column: 1, want: "BCET 3 exceeds WCET 2"},
+		{name: "duplicate task", content: header + "1, 10, 0, 1, 1, 2, 10, 1\n1, 20, 0, 1, 1, 2, 20, 2\n", line: 3, column: 1, want: "duplicate task 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadTaskSet(writeInput(t, "tasks.csv", tt.content), TimeModel{}, verbose.New("test"))
+			checkInputError(t, err, tt.line, tt.column, tt.want)
+		})
+	}
+}
+
+// TestReadTaskSetYAMLMissingFields checks that the required fields of yaml
+// tasks are reported at the task that lacks them, while the offset and the
+// jitter default to 0.
+func TestReadTaskSetYAMLMissingFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		line, column int
+		want         string
+	}{
+		{name: "period", content: "taskset:\n  - {Task ID: 1, BCET: 1, WCET: 2, Deadline: 10, Priority: 1}\n", line: 2, column: 5, want: "missing Period"},
+		{name: "wcet", content: "taskset:\n  - {Task ID: 1, Period: 10, BCET: 1, Deadline: 10, Priority: 1}\n", line: 2, column: 5, want: "missing WCET"},
+		{name: "dag priority", content: "dagtasks:\n  - Period: 10\n    Deadline: 10\n    Subtasks: [{Task ID: 1, BCET: 1, WCET: 2}]\n", line: 2, column: 5, want: "missing Priority"},
+		{name: "offset and jitter", content: "taskset:\n  - {Task ID: 1, Period: 10, BCET: 1, WCET: 2, Deadline: 10, Priority: 1}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := ReadTaskSetYAML(writeInput(t, "tasks.yaml", tt.content), TimeModel{}, verbose.New("test"))
+			if tt.want != "" {
+				checkInputError(t, err, tt.line, tt.column, tt.want)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tasks) != 1 || tasks[0].Offset != 0 || tasks[0].Jitter != 0 {
+				t.Errorf("tasks %v, want one task without offset and jitter", tasks)
+			}
+		})
+	}
+}
+
+// TestExpandDAGTask checks that the k-th job of each subtask of a fork-join
+// task is preceded by the k-th jobs of its predecessors over the
+// hyperperiod, which the plain task 5 makes twice the period of the DAG task.
+func TestExpandDAGTask(t *testing.T) {
+	const content = "dagtasks:\n" +
+		"  - {Period: 10, Offset: 2, Jitter: 1, Deadline: 10, Priority: 1,\n" +
+		"     Subtasks: [{Task ID: 1, BCET: 1, WCET: 1}, {Task ID: 2, BCET: 1, WCET: 1}, {Task ID: 3, BCET: 1, WCET: 1}, {Task ID: 4, BCET: 1, WCET: 1}],\n" +
+		"     Edges: [{From: 1, To: 2}, {From: 1, To: 3}, {From: 2, To: 4}, {From: 3, To: 4}]}\n" +
+		"taskset:\n" +
+		"  - {Task ID: 5, Period: 20, BCET: 1, WCET: 2, Deadline: 20, Priority: 2}\n"
+	tasks, err := ReadTaskSetYAML(writeInput(t, "tasks.yaml", content), TimeModel{}, verbose.New("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := tasks.Expand(0, TimeModel{})
+	if err == nil {
+		err = jobs.Validate(TimeModel{})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, j := range jobs {
+		s := j.Name + "@" + j.Arrival.String()
+		for _, pred := range j.Predecessors {
+			s += "<" + pred
+		}
+		got = append(got, s)
+	}
+	// the jobs are released before the hyperperiod ends after the offset 2
+	want := []string{
+		"J5,1@I[0,0]", "J5,2@I[20,20]",
+		"J1,1@I[2,3]", "J1,2@I[12,13]",
+		"J2,1@I[2,3]<J1,1", "J2,2@I[12,13]<J1,2",
+		"J3,1@I[2,3]<J1,1", "J3,2@I[12,13]<J1,2",
+		"J4,1@I[2,3]<J2,1<J3,1", "J4,2@I[12,13]<J2,2<J3,2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("jobs %v, want %v", got, want)
+	}
+}
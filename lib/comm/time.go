@@ -5,40 +5,86 @@ import (
 	"math"
 )
 
-var denseTimeModel bool = false
+// Time is a point in time or a duration. Times are exact 64-bit integers,
+// so that sums of release times and costs are never rounded. In the dense
+// time model, a Time counts fixed-point ticks of 1/denseTicks time units.
+type Time int64
 
-type DiscreteTime int32
+// denseTicks is the number of ticks per time unit in the dense time model,
+// i.e., dense times are exact up to six decimal places.
+const denseTicks = 1000000
 
-type DenseTime float32
+// TimeModel tells how the times of a job set are read, reasoned about and
+// written. Each analysis has its own, so that analyses with different time
+// models can run in parallel. The zero value is the discrete time model
+// with a resolution of one nanosecond.
+type TimeModel struct {
+	// Dense selects the dense time model.
+	Dense bool
+	// Resolution is the length of one time unit of the analysis, to which
+	// the times given with a unit are converted (0: Nanosecond).
+	Resolution Unit
+	// OutputUnit is the unit of the times in the output files (0: the time
+	// unit of the analysis).
+	OutputUnit Unit
+}
 
-type Time float32
+// ticksPerUnit returns the number of ticks of a Time per time unit.
+func (m TimeModel) ticksPerUnit() int64 {
+	if m.Dense {
+		return denseTicks
+	}
+	return 1
+}
 
-func WantDenseTimeModel() {
-	denseTimeModel = true
+// resolution returns the length of one time unit of the analysis.
+func (m TimeModel) resolution() Unit {
+	if m.Resolution == 0 {
+		return Nanosecond
+	}
+	return m.Resolution
 }
 
+// MaxTime is the largest magnitude of the times of a job set or task set.
+// It leaves enough headroom that the sum of a few times neither overflows
+// nor reaches Infinity().
+const MaxTime = Time(math.MaxInt64 / 4)
+
+// String formats t as an integer, i.e., in ticks in the dense time model.
 func (t Time) String() string {
-	if denseTimeModel {
-		return fmt.Sprintf("%f", DenseTime(t))
+	return t.Format(TimeModel{})
+}
+
+// Format formats t in time units of the time model m, and Infinity() as
+// "inf".
+func (t Time) Format(m TimeModel) string {
+	if t == Infinity() {
+		return "inf"
+	}
+	if m.Dense {
+		sign := ""
+		if t < 0 {
+			sign = "-"
+			t = -t
+		}
+		return fmt.Sprintf("%s%d.%06d", sign, int64(t/denseTicks), int64(t%denseTicks))
 	} else {
-		return fmt.Sprintf("%d", DiscreteTime(t))
+		return fmt.Sprintf("%d", int64(t))
 	}
 }
 
+// Infinity is the time after every other time, e.g., the release of a job
+// that does not exist. It absorbs the durations added to it with plus.
 func Infinity() Time {
-	if denseTimeModel {
-		return Time(math.MaxFloat32)
-	} else {
-		return Time(math.MaxInt32)
-	}
+	return Time(math.MaxInt64)
 }
 
-func Epsilon() Time {
-	if denseTimeModel {
-		return Time(math.SmallestNonzeroFloat32)
-	} else {
-		return Time(1)
+// plus returns t + d, or Infinity() if t is Infinity().
+func plus(t, d Time) Time {
+	if t == Infinity() {
+		return t
 	}
+	return t + d
 }
 
 func Maximum(t1, t2 Time) Time {
@@ -55,6 +101,20 @@ func Minimum(t1, t2 Time) Time {
 	return t2
 }
 
-func DeadlineMissTolerance() Time {
-	return Time(1e-6)
+// addTimes returns t1 + t2, or an error if the sum exceeds MaxTime.
+func addTimes(t1, t2 Time) (Time, error) {
+	if t2 > 0 && t1 > MaxTime-t2 || t2 < 0 && t1 < -MaxTime-t2 {
+		return 0, fmt.Errorf("time %s + %s is out of range", t1, t2)
+	}
+	return t1 + t2, nil
+}
+
+// checkTimeRange checks that the magnitude of every time is at most MaxTime.
+func checkTimeRange(times ...Time) error {
+	for _, t := range times {
+		if t > MaxTime || t < -MaxTime {
+			return fmt.Errorf("time %s is out of range", t)
+		}
+	}
+	return nil
 }
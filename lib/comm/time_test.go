@@ -0,0 +1,66 @@
+package comm
+
+import "testing"
+
+func TestAddTimes(t *testing.T) {
+	tests := []struct {
+		t1, t2 Time
+		want   Time
+		err    bool
+	}{
+		{t1: 2, t2: 3, want: 5},
+		{t1: -2, t2: 3, want: 1},
+		{t1: MaxTime - 1, t2: 1, want: MaxTime},
+		{t1: MaxTime, t2: 1, err: true},
+		{t1: -MaxTime, t2: -1, err: true},
+		{t1: 1, t2: MaxTime, err: true},
+	}
+	for _, tt := range tests {
+		got, err := addTimes(tt.t1, tt.t2)
+		if (err != nil) != tt.err {
+			t.Errorf("addTimes(%d, %d): error %v, want error %v", tt.t1, tt.t2, err, tt.err)
+		} else if err == nil && got != tt.want {
+			t.Errorf("addTimes(%d, %d) = %d, want %d", tt.t1, tt.t2, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTimeRange(t *testing.T) {
+	tests := []struct {
+		times []Time
+		err   bool
+	}{
+		{times: []Time{0, 1, -1}},
+		{times: []Time{MaxTime, -MaxTime}},
+		{times: []Time{0, MaxTime + 1}, err: true},
+		{times: []Time{-MaxTime - 1}, err: true},
+		{times: []Time{Infinity()}, err: true},
+	}
+	for _, tt := range tests {
+		if err := checkTimeRange(tt.times...); (err != nil) != tt.err {
+			t.Errorf("checkTimeRange(%v): error %v, want error %v", tt.times, err, tt.err)
+		}
+	}
+}
+
+func TestInfinityAbsorbs(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Bound
+		want Bound
+	}{
+		{"add", At(Infinity()).Add(5), At(Infinity())},
+		{"subtract", At(Infinity()).Add(-5), At(Infinity())},
+		{"before", TimeModel{}.Before(Infinity()), At(Infinity())},
+		{"after", TimeModel{}.After(Infinity()), At(Infinity())},
+		{"dense before", TimeModel{Dense: true}.Before(Infinity()), At(Infinity())},
+		{"before then add", TimeModel{}.Before(Infinity()).Add(MaxTime), At(Infinity())},
+		{"interval", Interval{Start: 1, End: Infinity()}.Add(Interval{Start: 2, End: 3}).Upper(), At(Infinity())},
+		{"finite", TimeModel{}.Before(10).Add(5), At(14)},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package comm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"math/big"
+	"strings"
+)
+
+// Unit is a unit of time, given by its length in nanoseconds.
+type Unit int64
+
+const (
+	Nanosecond  Unit = 1
+	Microsecond Unit = 1000 * Nanosecond
+	Millisecond Unit = 1000 * Microsecond
+	Second      Unit = 1000 * Millisecond
+)
+
+var unitNames = map[string]Unit{
+	"ns": Nanosecond,
+	"us": Microsecond,
+	"µs": Microsecond,
+	"ms": Millisecond,
+	"s":  Second,
+}
+
+// ParseUnit returns the unit with the given name (ns, us or µs, ms, s).
+func ParseUnit(name string) (Unit, error) {
+	if u, ok := unitNames[name]; ok {
+		return u, nil
+	}
+	return 0, fmt.Errorf("unknown time unit %q", name)
+}
+
+func (u Unit) String() string {
+	switch u {
+	case Nanosecond:
+		return "ns"
+	case Microsecond:
+		return "us"
+	case Millisecond:
+		return "ms"
+	case Second:
+		return "s"
+	}
+	return fmt.Sprintf("%dns", int64(u))
+}
+
+// OutputString formats t in the output unit of the time model m, as a
+// decimal number without trailing zeros. Without an output unit, it is
+// t.Format(m).
+func (t Time) OutputString(m TimeModel) string {
+	if m.OutputUnit == 0 || m.OutputUnit == m.resolution() || t == Infinity() {
+		return t.Format(m)
+	}
+	r := big.NewRat(int64(t), m.ticksPerUnit())
+	r.Mul(r, big.NewRat(int64(m.resolution()), int64(m.OutputUnit)))
+
+	// units are powers of ten, so the decimal expansion of r is finite
+	decimals := 0
+	for d := new(big.Int).Set(r.Denom()); d.Cmp(big.NewInt(1)) > 0; d.Quo(d, big.NewInt(10)) {
+		decimals++
+	}
+	return r.FloatString(decimals)
+}
+
+// errInexact is the error of a time that would have to be rounded.
+var errInexact = errors.New("is not a multiple of the resolution")
+
+// Rounding tells how a time is rounded to the resolution of the analysis.
+type Rounding int
+
+const (
+	// RoundDown is used for lower bounds, e.g., best-case costs and
+	// earliest releases.
+	RoundDown Rounding = iota
+	// RoundUp is used for upper bounds, e.g., worst-case costs, latest
+	// releases and deadlines.
+	RoundUp
+	// Exact is used for times that no rounding keeps safe, e.g., periods,
+	// which must be multiples of the resolution.
+	Exact
+)
+
+// ParseTime parses a time, e.g., "12", "2.5ms" or "500us", in the time model
+// m. A time without a unit is in time units of the analysis. The time is
+// converted to the resolution of m, rounded as given.
+func (m TimeModel) ParseTime(s string, rounding Rounding) (Time, error) {
+	return m.parseTimeIn(s, 0, rounding)
+}
+
+// parseTimeIn parses a time like ParseTime, but a time without a unit is in
+// unit (0: time units of the analysis).
+func (m TimeModel) parseTimeIn(s string, unit Unit, rounding Rounding) (Time, error) {
+	number := strings.TrimRight(s, "abcdefghijklmnopqrstuvwxyzµ")
+	if suffix := s[len(number):]; suffix != "" {
+		u, err := ParseUnit(suffix)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q: %v", s, err)
+		}
+		unit = u
+	}
+	number = strings.TrimSpace(number)
+
+	r, ok := new(big.Rat).SetString(number)
+	if !ok || strings.Contains(number, "/") {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	r.Mul(r, big.NewRat(m.ticksPerUnit(), 1))
+	if unit != 0 {
+		r.Mul(r, big.NewRat(int64(unit), int64(m.resolution())))
+	}
+
+	// floor or ceiling of r
+	t, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 && rounding == Exact {
+		return 0, fmt.Errorf("time %q %w", s, errInexact)
+	}
+	if rem.Sign() < 0 && rounding == RoundDown {
+		t.Sub(t, big.NewInt(1))
+	} else if rem.Sign() > 0 && rounding == RoundUp {
+		t.Add(t, big.NewInt(1))
+	}
+
+	if !t.IsInt64() || checkTimeRange(Time(t.Int64())) != nil {
+		return 0, fmt.Errorf("time %q is out of range", s)
+	}
+	return Time(t.Int64()), nil
+}
+
+// ParsePriority parses a priority. Priorities are compared with each other
+// only, so they have no unit and are not converted. They are not rounded
+// either, so that distinct priorities stay distinct: a priority with more
+// decimals than the time model, e.g., 1.5 in the discrete time model, is
+// rejected.
+func (m TimeModel) ParsePriority(s string) (Time, error) {
+	if strings.TrimRight(s, "abcdefghijklmnopqrstuvwxyzµ") != s {
+		return 0, fmt.Errorf("invalid priority %q, priorities have no unit", s)
+	}
+	p, err := m.parseTimeIn(s, 0, Exact)
+	if errors.Is(err, errInexact) {
+		if m.Dense {
+			return 0, fmt.Errorf("invalid priority %q, priorities have at most six decimals", s)
+		}
+		return 0, fmt.Errorf("invalid priority %q, priorities are integers", s)
+	}
+	return p, err
+}
+
+// timeValue is a time as written in a YAML or JSON file, which is converted
+// once the unit of the file is known.
+type timeValue string
+
+func (v *timeValue) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.ScalarNode {
+		return errors.New("a time must be a number, optionally with a unit")
+	}
+	*v = timeValue(value.Value)
+	return nil
+}
+
+func (v *timeValue) UnmarshalJSON(data []byte) error {
+	if data[0] == '"' {
+		return json.Unmarshal(data, (*string)(v))
+	}
+	*v = timeValue(data)
+	return nil
+}
+
+// timeParser converts the times of a file in the given unit to the time
+// model. It keeps the first error, so that all the times of an entry can be
+// converted before checking for an error.
+type timeParser struct {
+	model TimeModel
+	unit  Unit
+	err   error
+}
+
+// newTimeParser returns the parser of a file whose header declares the unit
+// with the given name, if any.
+func newTimeParser(m TimeModel, unitName string) (*timeParser, error) {
+	if unitName == "" {
+		return &timeParser{model: m}, nil
+	}
+	u, err := ParseUnit(unitName)
+	if err != nil {
+		return nil, err
+	}
+	return &timeParser{model: m, unit: u}, nil
+}
+
+// missing reports whether the time v is absent or null.
+func (v timeValue) missing() bool {
+	return v == "" || v == "null"
+}
+
+// fail keeps err unless an earlier error is kept.
+func (p *timeParser) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// time converts the required time v of the field name.
+func (p *timeParser) time(v timeValue, name string, rounding Rounding) Time {
+	if v.missing() {
+		p.fail(fmt.Errorf("missing %s", name))
+		return 0
+	}
+	return p.optionalTime(v, name, rounding)
+}
+
+// optionalTime converts the time v of the field name. A missing time is 0.
+func (p *timeParser) optionalTime(v timeValue, name string, rounding Rounding) Time {
+	if v.missing() || p.err != nil {
+		return 0
+	}
+	t, err := p.model.parseTimeIn(string(v), p.unit, rounding)
+	if err != nil {
+		p.fail(fmt.Errorf("%s: %v", name, err))
+	}
+	return t
+}
+
+// priority converts the required priority v.
+func (p *timeParser) priority(v timeValue) Time {
+	if v.missing() {
+		p.fail(errors.New("missing Priority"))
+	}
+	if v.missing() || p.err != nil {
+		return 0
+	}
+	t, err := p.model.ParsePriority(string(v))
+	if err != nil {
+		p.fail(fmt.Errorf("Priority: %v", err))
+	}
+	return t
+}
+
+// interval converts the required bounds min and max of the field name.
+func (p *timeParser) interval(min, max timeValue, name string) Interval {
+	return Interval{Start: p.time(min, name+" min", RoundDown), End: p.time(max, name+" max", RoundUp)}
+}
+
+// optionalInterval converts the bounds min and max of the field name. A
+// missing bound is 0.
+func (p *timeParser) optionalInterval(min, max timeValue, name string) Interval {
+	return Interval{Start: p.optionalTime(min, name+" min", RoundDown), End: p.optionalTime(max, name+" max", RoundUp)}
+}
+
+// sum adds the interval i of the field name to total, which must stay in
+// range.
+func (p *timeParser) sum(total, i Interval, name string) Interval {
+	var err error
+	if total.Start, err = addTimes(total.Start, i.Start); err == nil {
+		total.End, err = addTimes(total.End, i.End)
+	}
+	if err != nil {
+		p.fail(fmt.Errorf("%s: %v", name, err))
+	}
+	return total
+}
+
+// check returns the first error and resets it.
+func (p *timeParser) check() error {
+	err := p.err
+	p.err = nil
+	return err
+}
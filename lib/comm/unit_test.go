@@ -0,0 +1,88 @@
+package comm
+
+import "testing"
+
+func TestParseTime(t *testing.T) {
+	us := TimeModel{Resolution: Microsecond}
+	dense := TimeModel{Dense: true, Resolution: Millisecond}
+	tests := []struct {
+		model    TimeModel
+		s        string
+		rounding Rounding
+		want     Time
+		err      bool
+	}{
+		{model: TimeModel{}, s: "12", rounding: RoundUp, want: 12},
+		{model: TimeModel{}, s: "1us", rounding: RoundUp, want: 1000},
+		{model: TimeModel{}, s: "2.5", rounding: RoundDown, want: 2},
+		{model: TimeModel{}, s: "2.5", rounding: RoundUp, want: 3},
+		{model: TimeModel{}, s: "2.5", rounding: Exact, err: true},
+		{model: us, s: "1500ns", rounding: RoundDown, want: 1},
+		{model: us, s: "1500ns", rounding: RoundUp, want: 2},
+		{model: us, s: "1ms", rounding: Exact, want: 1000},
+		{model: us, s: "-1500ns", rounding: RoundDown, want: -2},
+		{model: us, s: "-1500ns", rounding: RoundUp, want: -1},
+		{model: dense, s: "1.5", rounding: Exact, want: 1500000},
+		{model: dense, s: "1500us", rounding: Exact, want: 1500000},
+		{model: dense, s: "1ns", rounding: Exact, want: 1},
+		{model: dense, s: "0.5ns", rounding: RoundDown, want: 0},
+		{model: dense, s: "0.5ns", rounding: RoundUp, want: 1},
+		{model: TimeModel{}, s: "1h", rounding: RoundUp, err: true},
+		{model: TimeModel{}, s: "1/2", rounding: RoundUp, err: true},
+		{model: TimeModel{}, s: "abc", rounding: RoundUp, err: true},
+		{model: TimeModel{}, s: "1e30", rounding: RoundUp, err: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.model.ParseTime(tt.s, tt.rounding)
+		if (err != nil) != tt.err {
+			t.Errorf("%+v.ParseTime(%q, %d): error %v, want error %v", tt.model, tt.s, tt.rounding, err, tt.err)
+		} else if err == nil && got != tt.want {
+			t.Errorf("%+v.ParseTime(%q, %d) = %d, want %d", tt.model, tt.s, tt.rounding, got, tt.want)
+		}
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		model TimeModel
+		s     string
+		want  Time
+		err   bool
+	}{
+		{model: TimeModel{}, s: "3", want: 3},
+		{model: TimeModel{Resolution: Millisecond}, s: "3", want: 3},
+		{model: TimeModel{}, s: "1.5", err: true},
+		{model: TimeModel{}, s: "2ms", err: true},
+		{model: TimeModel{Dense: true}, s: "1.5", want: 1500000},
+		{model: TimeModel{Dense: true}, s: "1.0000005", err: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.model.ParsePriority(tt.s)
+		if (err != nil) != tt.err {
+			t.Errorf("%+v.ParsePriority(%q): error %v, want error %v", tt.model, tt.s, err, tt.err)
+		} else if err == nil && got != tt.want {
+			t.Errorf("%+v.ParsePriority(%q) = %d, want %d", tt.model, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestOutputString(t *testing.T) {
+	tests := []struct {
+		model TimeModel
+		t     Time
+		want  string
+	}{
+		{model: TimeModel{}, t: 1500, want: "1500"},
+		{model: TimeModel{OutputUnit: Microsecond}, t: 1500, want: "1.5"},
+		{model: TimeModel{OutputUnit: Microsecond}, t: -1500, want: "-1.5"},
+		{model: TimeModel{Resolution: Microsecond, OutputUnit: Microsecond}, t: 1500, want: "1500"},
+		{model: TimeModel{Resolution: Millisecond, OutputUnit: Microsecond}, t: 2, want: "2000"},
+		{model: TimeModel{Dense: true}, t: 1500000, want: "1.500000"},
+		{model: TimeModel{Dense: true, OutputUnit: Microsecond}, t: 1500000, want: "0.0015"},
+	}
+	for _, tt := range tests {
+		if got := tt.t.OutputString(tt.model); got != tt.want {
+			t.Errorf("%d.OutputString(%+v) = %q, want %q", tt.t, tt.model, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package comm
+
+import (
+	"fmt"
+)
+
+// InputError is an error in an input file, located by its line and column
+// (0 if unknown).
+type InputError struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *InputError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks that the parameters of job j are consistent. The times of
+// the error are formatted in the time model m.
+func (j Job) Validate(m TimeModel) error {
+	if j.Arrival.Start > j.Arrival.End {
+		return fmt.Errorf("job %s: earliest arrival %s is after latest arrival %s", j.Name, j.Arrival.Start.Format(m), j.Arrival.End.Format(m))
+	}
+	if j.Arrival.Start < 0 {
+		return fmt.Errorf("job %s: negative arrival %s", j.Name, j.Arrival.Start.Format(m))
+	}
+	if j.Cost.Start > j.Cost.End {
+		return fmt.Errorf("job %s: best-case cost %s exceeds worst-case cost %s", j.Name, j.Cost.Start.Format(m), j.Cost.End.Format(m))
+	}
+	if j.Cost.Start < 0 || j.Cost.End <= 0 {
+		return fmt.Errorf("job %s: the worst-case cost must be positive and the best-case cost non-negative", j.Name)
+	}
+	if err := checkTimeRange(j.Arrival.Start, j.Arrival.End, j.Cost.Start, j.Cost.End, j.Deadline); err != nil {
+		return fmt.Errorf("job %s: %v", j.Name, err)
+	}
+	// the cost of a segmented job is the sum of its segments, which must
+	// not have overflowed
+	var cost Interval
+	for k, segment := range j.Segments {
+		if segment.Start > segment.End || segment.Start < 0 || segment.End <= 0 {
+			return fmt.Errorf("job %s: invalid cost %s of segment %d", j.Name, segment.Format(m), k+1)
+		}
+		var err error
+		if cost.Start, err = addTimes(cost.Start, segment.Start); err == nil {
+			cost.End, err = addTimes(cost.End, segment.End)
+		}
+		if err != nil {
+			return fmt.Errorf("job %s: the cost of the segments is out of range", j.Name)
+		}
+	}
+	if len(j.Segments) > 0 && cost != j.Cost {
+		return fmt.Errorf("job %s: cost %s is not the sum of the segments %s", j.Name, j.Cost.Format(m), cost.Format(m))
+	}
+	if len(j.Suspensions) > 0 && len(j.Suspensions) != len(j.Segments)-1 {
+		return fmt.Errorf("job %s: %d suspensions need %d segments", j.Name, len(j.Suspensions), len(j.Suspensions)+1)
+	}
+	for pred, delay := range j.PredecessorDelays {
+		if delay.Start > delay.End || delay.Start < 0 || checkTimeRange(delay.End) != nil {
+			return fmt.Errorf("job %s: invalid delay %s after predecessor %s", j.Name, delay.Format(m), pred)
+		}
+	}
+	for k, suspension := range j.Suspensions {
+		if suspension.Start > suspension.End || suspension.Start < 0 || checkTimeRange(suspension.End) != nil {
+			return fmt.Errorf("job %s: invalid suspension %s after segment %d", j.Name, suspension.Format(m), k+1)
+		}
+	}
+	return nil
+}
+
+// Validate checks the jobs of S, the uniqueness of their IDs and their
+// precedence constraints, which must refer to jobs of S and be acyclic.
+func (S JobSet) Validate(m TimeModel) error {
+	byName := make(map[string]*Job, len(S))
+	for _, j := range S {
+		if err := j.Validate(m); err != nil {
+			return err
+		}
+		if _, exists := byName[j.Name]; exists {
+			return fmt.Errorf("duplicate job %s (Task ID %d, Job ID %d)", j.Name, j.TaskID, j.JobID)
+		}
+		byName[j.Name] = j
+	}
+
+	for _, j := range S {
+		for _, pred := range j.Predecessors {
+			if _, exists := byName[pred]; !exists {
+				return fmt.Errorf("job %s: unknown predecessor %s", j.Name, pred)
+			}
+		}
+	}
+
+	// depth-first search for a back edge
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(S))
+	var visit func(j *Job) error
+	visit = func(j *Job) error {
+		marks[j.Name] = visiting
+		for _, pred := range j.Predecessors {
+			switch marks[pred] {
+			case visiting:
+				return fmt.Errorf("precedence cycle through jobs %s and %s", pred, j.Name)
+			case unvisited:
+				if err := visit(byName[pred]); err != nil {
+					return err
+				}
+			}
+		}
+		marks[j.Name] = visited
+		return nil
+	}
+	for _, j := range S {
+		if marks[j.Name] == unvisited {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := S.horizon(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// horizon returns the latest release of the jobs of S plus all their costs,
+// suspensions and delays. No job of S can complete later, so that the times
+// computed by the analysis do not overflow if the horizon is in range.
+func (S JobSet) horizon() (Time, error) {
+	h := Time(0)
+	for _, j := range S {
+		h = Maximum(h, j.Arrival.End)
+	}
+
+	var err error
+	add := func(t Time) {
+		if err == nil {
+			h, err = addTimes(h, t)
+		}
+	}
+	for _, j := range S {
+		add(j.Cost.End)
+		for _, suspension := range j.Suspensions {
+			add(suspension.End)
+		}
+		for _, delay := range j.PredecessorDelays {
+			add(delay.End)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("the job set is too long to be analysed: %v", err)
+	}
+	return h, nil
+}
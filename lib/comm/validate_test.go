@@ -0,0 +1,71 @@
+package comm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJobSetValidate(t *testing.T) {
+	newJob := func(name string, predecessors ...string) *Job {
+		return &Job{Name: name, Arrival: Interval{Start: 0, End: 1}, Cost: Interval{Start: 1, End: 2}, Deadline: 10, Predecessors: predecessors}
+	}
+	tests := []struct {
+		name string
+		jobs JobSet
+		want string
+	}{
+		{name: "chain", jobs: JobSet{newJob("J1"), newJob("J2", "J1"), newJob("J3", "J1", "J2")}},
+		{name: "duplicate job", jobs: JobSet{newJob("J1"), newJob("J1")}, want: "duplicate job J1"},
+		{name: "unknown predecessor", jobs: JobSet{newJob("J1", "J9")}, want: "unknown predecessor J9"},
+		{name: "self loop", jobs: JobSet{newJob("J1", "J1")}, want: "precedence cycle"},
+		{name: "cycle", jobs: JobSet{newJob("J1", "J3"), newJob("J2", "J1"), newJob("J3", "J2")}, want: "precedence cycle"},
+		{name: "invalid job", jobs: JobSet{{Name: "J1", Arrival: Interval{Start: 0, End: 1}, Cost: Interval{Start: 3, End: 2}}}, want: "best-case cost 3 exceeds worst-case cost 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.jobs.Validate(TimeModel{})
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("error %v, want none", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestJobSetHorizon checks that job sets whose finish times could overflow
+// are rejected.
+func TestJobSetHorizon(t *testing.T) {
+	newJob := func(name string, arrival, cost Time) *Job {
+		return &Job{Name: name, Arrival: Interval{Start: 0, End: arrival}, Cost: Interval{Start: 1, End: cost}, Deadline: MaxTime}
+	}
+	tests := []struct {
+		name string
+		jobs JobSet
+		want string
+	}{
+		{name: "in range", jobs: JobSet{newJob("J1", MaxTime/2, MaxTime/4), newJob("J2", 0, MaxTime/4)}},
+		{name: "time out of range", jobs: JobSet{newJob("J1", MaxTime+1, 1)}, want: "job J1"},
+		{name: "latest release plus costs", jobs: JobSet{newJob("J1", MaxTime, 1), newJob("J2", 0, 1)}, want: "too long"},
+		{name: "sum of costs", jobs: JobSet{newJob("J1", 0, MaxTime/2), newJob("J2", 0, MaxTime/2), newJob("J3", 0, MaxTime/2)}, want: "too long"},
+		{
+			name: "delays",
+			jobs: JobSet{newJob("J1", 0, MaxTime/2), {Name: "J2", Arrival: Interval{Start: 0, End: 0}, Cost: Interval{Start: 1, End: 1}, Deadline: 10, Predecessors: []string{"J1"}, PredecessorDelays: map[string]Interval{"J1": {Start: 0, End: MaxTime}}}},
+			want: "too long",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.jobs.Validate(TimeModel{})
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("error %v, want none", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
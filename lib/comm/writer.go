@@ -2,39 +2,227 @@ package comm
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 )
 
-func WriteResponseTimes(filename string, rta map[string]Interval, workload JobSet) {
-	csvFile, err := os.Create(filename)
-	defer csvFile.Close()
+// createOutput creates the output file filename, or returns the standard
+// output for "-".
+func createOutput(filename string) (*os.File, error) {
+	if filename == "-" {
+		return os.Stdout, nil
+	}
+	return os.Create(filename)
+}
+
+func closeOutput(f *os.File) error {
+	if f != os.Stdout {
+		return f.Close()
+	}
+	return nil
+}
+
+// writeOutput writes the output file filename, or the standard output for
+// "-", with write. It returns the first error of creating, writing and
+// closing the file.
+func writeOutput(filename string, write func(f *os.File) error) error {
+	f, err := createOutput(filename)
 	if err != nil {
-		log.Fatalf("failed creating file: %s", err)
+		return err
 	}
+	err = write(f)
+	if cerr := closeOutput(f); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// writeCSV writes rows to the csv file filename.
+func writeCSV(filename string, rows [][]string) error {
+	return writeOutput(filename, func(f *os.File) error {
+		return csv.NewWriter(f).WriteAll(rows)
+	})
+}
+
+// writeJSON writes v to the json file filename.
+func writeJSON(filename string, v interface{}) error {
+	return writeOutput(filename, func(f *os.File) error {
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	})
+}
 
-	w := csv.NewWriter(csvFile)
-	defer w.Flush()
+// jsonTime returns t as a json number in the output unit of m, or nil, i.e.,
+// null, for Infinity().
+func jsonTime(t Time, m TimeModel) *json.Number {
+	if t == Infinity() {
+		return nil
+	}
+	n := json.Number(t.OutputString(m))
+	return &n
+}
 
-	//	write header
-	row := []string{"Task ID", "Job ID", "BCCT", "WCCT", "BCRT", "WCRT"}
-	if err := w.Write(row); err != nil {
-		log.Fatalln("error writing record to file", err)
+// responseTime returns the response time of job j if it completes at time
+// t, which is Infinity() if it never completes.
+func responseTime(t Time, j *Job) Time {
+	if t == Infinity() {
+		return t
 	}
+	return t - j.Arrival.Start
+}
+
+// WriteResponseTimes writes the completion and response times of the jobs
+// of workload in csv format. The times of the jobs without an entry in rta,
+// which the exploration never reached, are left empty.
+func WriteResponseTimes(filename string, rta map[string]Interval, workload JobSet, m TimeModel) error {
+	//	header
+	rows := [][]string{{"Task ID", "Job ID", "BCCT", "WCCT", "BCRT", "WCRT"}}
 
-	//	write data
+	//	data
 	for _, j := range workload {
-		row := []string{
-			fmt.Sprint(j.TaskID),
-			fmt.Sprint(j.JobID),
-			fmt.Sprint(rta[j.Name].Start.String()),
-			fmt.Sprint(rta[j.Name].End.String()),
-			fmt.Sprint((rta[j.Name].Start - j.Arrival.Start).String()),
-			fmt.Sprint((rta[j.Name].End - j.Arrival.Start).String()),
+		row := []string{fmt.Sprint(j.TaskID), fmt.Sprint(j.JobID), "", "", "", ""}
+		if finishTime, ok := rta[j.Name]; ok {
+			row[2] = finishTime.Start.OutputString(m)
+			row[3] = finishTime.End.OutputString(m)
+			row[4] = responseTime(finishTime.Start, j).OutputString(m)
+			row[5] = responseTime(finishTime.End, j).OutputString(m)
 		}
-		if err := w.Write(row); err != nil {
-			log.Fatalln("error writing record to file", err)
+		rows = append(rows, row)
+	}
+	return writeCSV(filename, rows)
+}
+
+// WriteResultJSON writes the outcome of an analysis in JSON format. The
+// options are written as they are, so that the result can be traced back
+// to the analysis that produced it. The times are in the output unit, which
+// is written as "unit" if one is set. The times of the jobs that the
+// exploration never reached, and the infinite times of the jobs that may
+// never complete, are null.
+func WriteResultJSON(filename string, r *AnalysisResult, options interface{}) error {
+	type jsonJob struct {
+		TaskID uint         `json:"Task ID"`
+		JobID  uint         `json:"Job ID"`
+		BCCT   *json.Number `json:"BCCT"`
+		WCCT   *json.Number `json:"WCCT"`
+		BCRT   *json.Number `json:"BCRT"`
+		WCRT   *json.Number `json:"WCRT"`
+	}
+	type jsonStatistics struct {
+		States  uint    `json:"states"`
+		Edges   uint    `json:"edges"`
+		Depth   uint    `json:"depth"`
+		Runtime float64 `json:"runtime"`
+	}
+	type jsonResult struct {
+		Verdict    string         `json:"verdict"`
+		Options    interface{}    `json:"options"`
+		Unit       string         `json:"unit,omitempty"`
+		Statistics jsonStatistics `json:"statistics"`
+		Jobs       []jsonJob      `json:"jobs"`
+	}
+
+	result := jsonResult{
+		Verdict: r.Verdict(),
+		Options: options,
+		Statistics: jsonStatistics{
+			States:  r.Statistics.NumberOfStates,
+			Edges:   r.Statistics.NumberOfEdges,
+			Depth:   r.Statistics.Depth,
+			Runtime: r.Statistics.CPUTime.Seconds(),
+		},
+		Jobs: []jsonJob{},
+	}
+	m := r.TimeModel
+	if m.OutputUnit != 0 {
+		result.Unit = m.OutputUnit.String()
+	}
+	for _, j := range r.Workload {
+		job := jsonJob{TaskID: j.TaskID, JobID: j.JobID}
+		if rta, ok := r.ResponseTimes[j.Name]; ok {
+			job.BCCT = jsonTime(rta.Start, m)
+			job.WCCT = jsonTime(rta.End, m)
+			job.BCRT = jsonTime(responseTime(rta.Start, j), m)
+			job.WCRT = jsonTime(responseTime(rta.End, j), m)
+		}
+		result.Jobs = append(result.Jobs, job)
+	}
+	return writeJSON(filename, result)
+}
+
+// WriteMisses writes the jobs of r that can miss their deadline in csv
+// format, one per line, with the time by which they can miss it and the
+// number of jobs of their task that can miss their deadline.
+func WriteMisses(filename string, r *AnalysisResult) error {
+	rows := [][]string{{"Task ID", "Job ID", "Deadline", "WCCT", "Overshoot", "Task misses"}}
+
+	taskMisses := make(map[uint]int)
+	for _, t := range r.TaskMisses() {
+		taskMisses[t.TaskID] = t.Misses
+	}
+	for _, m := range r.JobMisses() {
+		row := []string{
+			fmt.Sprint(m.Job.TaskID),
+			fmt.Sprint(m.Job.JobID),
+			m.Job.Deadline.OutputString(r.TimeModel),
+			m.WCCT.OutputString(r.TimeModel),
+			m.Overshoot().OutputString(r.TimeModel),
+			fmt.Sprint(taskMisses[m.Job.TaskID]),
 		}
+		rows = append(rows, row)
+	}
+	return writeCSV(filename, rows)
+}
+
+// WriteMissesJSON writes the jobs of r that can miss their deadline, and the
+// number of such jobs per task, in json format. Infinite times are null.
+func WriteMissesJSON(filename string, r *AnalysisResult) error {
+	type jsonJob struct {
+		TaskID    uint         `json:"Task ID"`
+		JobID     uint         `json:"Job ID"`
+		Deadline  *json.Number `json:"Deadline"`
+		WCCT      *json.Number `json:"WCCT"`
+		Overshoot *json.Number `json:"Overshoot"`
+	}
+	type jsonTask struct {
+		TaskID       uint         `json:"Task ID"`
+		Jobs         int          `json:"Jobs"`
+		Misses       int          `json:"Misses"`
+		MaxOvershoot *json.Number `json:"Max overshoot"`
+	}
+	type jsonReport struct {
+		Verdict string     `json:"verdict"`
+		Unit    string     `json:"unit,omitempty"`
+		Jobs    []jsonJob  `json:"jobs"`
+		Tasks   []jsonTask `json:"tasks"`
+	}
+
+	report := jsonReport{
+		Verdict: r.Verdict(),
+		Jobs:    []jsonJob{},
+		Tasks:   []jsonTask{},
+	}
+	tm := r.TimeModel
+	if tm.OutputUnit != 0 {
+		report.Unit = tm.OutputUnit.String()
+	}
+	for _, m := range r.JobMisses() {
+		report.Jobs = append(report.Jobs, jsonJob{
+			TaskID:    m.Job.TaskID,
+			JobID:     m.Job.JobID,
+			Deadline:  jsonTime(m.Job.Deadline, tm),
+			WCCT:      jsonTime(m.WCCT, tm),
+			Overshoot: jsonTime(m.Overshoot(), tm),
+		})
+	}
+	for _, t := range r.TaskMisses() {
+		report.Tasks = append(report.Tasks, jsonTask{
+			TaskID:       t.TaskID,
+			Jobs:         t.Jobs,
+			Misses:       t.Misses,
+			MaxOvershoot: jsonTime(t.MaxOvershoot, tm),
+		})
 	}
+	return writeJSON(filename, report)
 }
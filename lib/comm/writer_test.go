@@ -0,0 +1,68 @@
+package comm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUnexploredJobs checks that the jobs that an exploration stopped early
+// never reached are not written as if they completed at time 0.
+func TestUnexploredJobs(t *testing.T) {
+	j1 := &Job{Name: "J1", TaskID: 1, JobID: 1, Arrival: Interval{Start: 0, End: 0}, Cost: Interval{Start: 1, End: 2}, Deadline: 1}
+	j2 := &Job{Name: "J2", TaskID: 2, JobID: 1, Arrival: Interval{Start: 0, End: 0}, Cost: Interval{Start: 1, End: 2}, Deadline: 10}
+	r := &AnalysisResult{
+		Workload:      JobSet{j1, j2},
+		ResponseTimes: map[string]Interval{"J1": {Start: 1, End: 2}},
+		DeadlineMiss:  true,
+		Aborted:       true,
+	}
+	dir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		write func(filename string) error
+		want  string
+	}{
+		{
+			name: "csv",
+			write: func(filename string) error {
+				return r.WriteResponseTimes(filename)
+			},
+			want: "Task ID,Job ID,BCCT,WCCT,BCRT,WCRT\n1,1,1,2,1,2\n2,1,,,,\n",
+		},
+		{
+			name: "json",
+			write: func(filename string) error {
+				return r.WriteJSON(filename, nil)
+			},
+			want: `"BCCT": null`,
+		},
+		{
+			name: "print",
+			write: func(filename string) error {
+				var b bytes.Buffer
+				r.FprintResponseTimes(&b)
+				return os.WriteFile(filename, b.Bytes(), 0644)
+			},
+			want: "J2 :  unexplored\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tt.name)
+			if err := tt.write(filename); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("output %q does not contain %q", got, tt.want)
+			}
+		})
+	}
+}
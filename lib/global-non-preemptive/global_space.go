@@ -0,0 +1,568 @@
+package global_non_preemptive
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/comm"
+	"time"
+)
+
+type responseTimes map[string]comm.Interval
+
+// Space is the schedule-abstraction graph of a job set. It holds all the data
+// of one exploration, so independent spaces can be explored concurrently.
+type Space struct {
+	beNaive bool
+	dag     *comm.DAG
+	states  *StateStorage
+
+	// noGraph skips the construction of the graph, so that only the
+	// states that are yet to be explored are kept.
+	noGraph  bool
+	numEdges uint
+
+	statesIndex     uint
+	currentJobCount int
+
+	startTime   time.Time
+	elapsedTime time.Duration
+
+	jobsByEarliestArrival comm.JobSet
+	jobsByLatestArrival   comm.JobSet
+	jobsByDeadline        comm.JobSet
+	jobsByPriority        comm.JobSet
+	workload              comm.JobSet
+
+	// response times
+	rta responseTimes
+
+	timeout   uint
+	maxDepth  uint
+	earlyExit bool
+	// threads is the number of goroutines that expand the states of a depth
+	threads int
+
+	aborted       bool
+	deadlineMiss  bool
+	timedOut      bool
+	depthExceeded bool
+	// misses are the dispatches after which a job can miss its deadline
+	misses []comm.MissedDeadline
+
+	// numberOfCores is the number of identical cores of the platform.
+	numberOfCores uint
+
+	// successors of each job, used to know when a finish time can be forgotten
+	successors map[string][]int
+
+	timeModel comm.TimeModel
+	logger    *verbose.Logger
+}
+
+// NewSpace prepares the exploration of job set w. The jobs are copied, so w
+// is never modified by the analysis.
+func NewSpace(w comm.JobSet, opts comm.AnalysisOptions) *Space {
+	sp := &Space{
+		beNaive:       opts.Naive,
+		workload:      w.Clone(),
+		rta:           make(responseTimes),
+		timeout:       opts.Timeout,
+		maxDepth:      opts.MaxDepth,
+		earlyExit:     opts.EarlyExit,
+		noGraph:       opts.NoGraph,
+		threads:       opts.Threads,
+		numberOfCores: opts.Cores,
+		timeModel:     opts.TimeModel,
+		logger:        opts.Logger,
+	}
+	sp.workload.IndexJobs()
+	if sp.numberOfCores == 0 {
+		sp.numberOfCores = 1
+	}
+	if sp.logger == nil {
+		sp.logger = verbose.New("NP::Global")
+	}
+
+	return sp
+}
+
+// Explore builds the schedule-abstraction graph and returns the result of
+// the analysis.
+func (sp *Space) Explore() *comm.AnalysisResult {
+	sp.startTime = time.Now()
+	sp.explore()
+	sp.elapsedTime = time.Since(sp.startTime)
+
+	return &comm.AnalysisResult{
+		Workload:      sp.workload,
+		TimeModel:     sp.timeModel,
+		ResponseTimes: sp.rta,
+		DeadlineMiss:  sp.deadlineMiss,
+		Misses:        sp.misses,
+		Aborted:       sp.aborted,
+		TimedOut:      sp.timedOut,
+		DepthExceeded: sp.depthExceeded,
+		Statistics:    sp.statistics(),
+		Graph:         sp.dag,
+	}
+}
+
+func (sp *Space) explore() {
+	sp.jobsByEarliestArrival = make(comm.JobSet, len(sp.workload))
+	sp.jobsByLatestArrival = make(comm.JobSet, len(sp.workload))
+	sp.jobsByDeadline = make(comm.JobSet, len(sp.workload))
+	sp.jobsByPriority = make(comm.JobSet, len(sp.workload))
+
+	copy(sp.jobsByEarliestArrival, sp.workload)
+	copy(sp.jobsByLatestArrival, sp.workload)
+	copy(sp.jobsByDeadline, sp.workload)
+	copy(sp.jobsByPriority, sp.workload)
+
+	sp.jobsByEarliestArrival.SortByEarliestArrival()
+	sp.jobsByLatestArrival.SortByLatestArrival()
+	sp.jobsByDeadline.SortByDeadline()
+	sp.jobsByPriority.SortByPriority()
+
+	sp.successors = make(map[string][]int)
+	for _, j := range sp.workload {
+		for _, p := range j.GetPredecessors() {
+			sp.successors[p] = append(sp.successors[p], j.Index)
+		}
+	}
+
+	sp.initialize()
+
+	for sp.currentJobCount < len(sp.workload) {
+		if sp.maxDepth > 0 && uint(sp.currentJobCount) >= sp.maxDepth {
+			sp.logger.Warning("---> Depth limit exceeded!")
+			sp.depthExceeded = true
+			sp.aborted = true
+			break
+		}
+
+		frontStates := sp.getFrontStates()
+		// the states of a depth are expanded concurrently, and their
+		// successors are added in order, as in a sequential exploration
+		successors := make([][]successor, len(frontStates))
+		comm.ParallelFor(len(frontStates), sp.threads, func(i int) {
+			if !sp.isTimedOut() {
+				successors[i] = sp.exploreState(frontStates[i])
+			}
+		})
+		for i, s := range frontStates {
+			if sp.isTimedOut() {
+				sp.logger.Warning("---> Timeout!")
+				sp.timedOut = true
+				sp.aborted = true
+				break
+			}
+
+			for _, succ := range successors[i] {
+				sp.addSuccessor(s, succ)
+			}
+			if len(sp.misses) > 0 && sp.earlyExit {
+				sp.aborted = true
+				break
+			}
+			foundJob := len(successors[i]) > 0
+			if !foundJob && s.ScheduledJobs.Len() != len(sp.workload) {
+				// out of options and we didn't schedule all jobs
+				sp.deadEnd(s)
+
+				if sp.earlyExit {
+					sp.aborted = true
+					break
+				}
+			}
+		}
+		if sp.aborted {
+			sp.logger.Warning("---> Aborted!")
+			break
+		}
+
+		sp.currentJobCount++
+	}
+
+}
+
+func (sp *Space) isTimedOut() bool {
+	return sp.timeout > 0 && time.Since(sp.startTime) > time.Duration(sp.timeout)*time.Second
+}
+
+// exploreState returns the successors of state s. It does not modify the
+// space, so that several states can be explored concurrently.
+func (sp *Space) exploreState(s *State) []successor {
+	var successors []successor
+
+	sp.logger.Debug("==========================================")
+	sp.logger.Debug("Looking at: ", s.GetName())
+
+	// t_core: the earliest core is certainly available
+	tCore := s.Availability().Until()
+	// t_job: some incomplete job is certainly ready
+	tJob := sp.nextJobReady(s)
+	// t_wc: a work-conserving scheduler certainly dispatches some job
+	tWc := comm.Maximum(tCore, tJob)
+
+	sp.logger.Debug("Core availability: ", s.coreString(", ", sp.timeModel))
+	sp.logger.Debug("t_core: ", tCore)
+	sp.logger.Debug("t_job: ", tJob)
+	sp.logger.Debug("t_wc: ", tWc)
+
+	// Iterate over all incomplete jobs that are released no later than t_wc
+	for _, jt := range sp.jobsByEarliestArrival {
+		if jt.Arrival.Start < s.EarliestPendingRelease {
+			continue
+		}
+
+		if isDispatched(s.ScheduledJobs, *jt) {
+			continue
+		}
+
+		if jt.GetEarliestArrival() > tWc {
+			break
+		}
+
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
+		sp.logger.Debug("+ ", jt.Name)
+		if succ, ok := sp.dispatch(s, *jt, tWc); ok {
+			sp.logger.Debug("  --> can be next ")
+			successors = append(successors, succ)
+		}
+	}
+
+	return successors
+}
+
+func (sp *Space) initialize() {
+	if !sp.noGraph {
+		sp.dag = comm.NewDAG()
+	}
+	sp.states = NewStateStorage()
+
+	// make root state
+	s0 := NewInitialState(sp.statesIndex, sp.numberOfCores, len(sp.workload))
+
+	if !sp.noGraph {
+		v1, _ := sp.dag.AddVertex(s0.GetName(), s0.GetLabel(sp.timeModel))
+		s0.ID = v1
+	}
+	sp.states.AddState(s0)
+
+	sp.statesIndex++
+
+}
+
+func (sp *Space) makeState(coreAvailability []comm.Interval, jobs comm.JobBitSet, finishTimes map[string]comm.Interval,
+	earliestReleasePending comm.Time, parentState *State, dispatchedJob comm.Job, startRange comm.Interval,
+	finishTime comm.Interval) {
+
+	s := NewState(sp.statesIndex, coreAvailability, jobs, finishTimes, earliestReleasePending)
+	if !sp.noGraph {
+		newStateID, _ := sp.dag.AddVertex(s.GetName(), s.GetLabel(sp.timeModel))
+		s.ID = newStateID
+	}
+
+	sp.states.AddState(s)
+
+	sp.numEdges++
+	if !sp.noGraph {
+		sp.dag.AddEdge(parentState.GetID(), s.GetID(), edgeLabel(dispatchedJob, startRange, finishTime, sp.timeModel))
+	}
+	sp.statesIndex++
+
+	sp.logger.Debug("Make state: ", s.GetName())
+	sp.logger.Debug("Core availability: ", s.coreString(", ", sp.timeModel))
+	sp.logger.Debug("Earliest pending release: ", s.EarliestPendingRelease)
+	sp.logger.Debug("Scheduled jobs: ", s.ScheduledJobs.Jobs(sp.workload).AbstractString())
+	sp.logger.Debug("----------------------------------------")
+}
+
+func edgeLabel(dispatchedJob comm.Job, startRange comm.Interval, finishTime comm.Interval, m comm.TimeModel) string {
+	label := dispatchedJob.Name + "\\nDL=" + dispatchedJob.Deadline.Format(m)
+	label += "\\nES=" + startRange.Start.Format(m) + "\\nLS=" + startRange.End.Format(m)
+	label += "\\nEF=" + finishTime.Start.Format(m) + "\\nLF=" + finishTime.Upper().Format(m)
+	return label
+}
+
+func (sp *Space) statistics() comm.Statistics {
+	return comm.Statistics{
+		NumberOfStates: sp.statesIndex,
+		NumberOfEdges:  sp.numEdges,
+		Depth:          uint(sp.currentJobCount),
+		CPUTime:        sp.elapsedTime,
+	}
+}
+
+// getFrontStates returns the states at the current depth. They are removed
+// from the storage, as every state made from them is deeper.
+func (sp *Space) getFrontStates() []*State {
+	return sp.states.PopDepth(sp.currentJobCount)
+}
+
+func isDispatched(jobs comm.JobBitSet, job comm.Job) bool {
+	return jobs.Contains(job.Index)
+}
+
+func (sp *Space) ready(state *State, job comm.Job) bool {
+	if !state.ScheduledJobs.ContainsAll(job.PredecessorIndices) {
+		return false
+	}
+
+	return true
+}
+
+// readyTimes returns the interval during which job j becomes ready in state s,
+// i.e. it is released and all of its predecessors have finished, plus the
+// delays of the precedence edges.
+func (sp *Space) readyTimes(s *State, j comm.Job) comm.Interval {
+	r := j.Arrival
+	for _, pred := range j.GetPredecessors() {
+		if ft, ok := s.FinishTimes[pred]; ok {
+			delay := j.GetPredecessorDelay(pred)
+			r.Start = comm.Maximum(r.Start, ft.Start+delay.Start)
+			r.End = comm.Maximum(r.End, ft.End+delay.End)
+		}
+	}
+	return r
+}
+
+// nextJobReady returns the earliest time at which some incomplete job is
+// certainly ready.
+func (sp *Space) nextJobReady(s *State) comm.Time {
+	when := comm.Infinity()
+	for _, jt := range sp.jobsByLatestArrival {
+		// the ready time is never earlier than the latest arrival
+		if jt.GetLatestArrival() >= when {
+			break
+		}
+
+		if isDispatched(s.ScheduledJobs, *jt) {
+			continue
+		}
+
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
+		when = comm.Minimum(when, sp.readyTimes(s, *jt).Until())
+	}
+	return when
+}
+
+// nextHigherPriorityJobReady returns the earliest time at which an incomplete
+// job with a higher priority than j is certainly ready.
+func (sp *Space) nextHigherPriorityJobReady(s *State, j comm.Job) comm.Time {
+	when := comm.Infinity()
+	for _, jt := range sp.jobsByLatestArrival {
+		if jt.GetLatestArrival() >= when {
+			break
+		}
+
+		if isDispatched(s.ScheduledJobs, *jt) {
+			continue
+		}
+
+		// skip reference job
+		if jt.SameJob(j) {
+			continue
+		}
+
+		if !jt.HigherPriorityThan(j) {
+			continue
+		}
+
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
+		when = comm.Minimum(when, sp.readyTimes(s, *jt).Until())
+	}
+	return when
+}
+
+// startTimes returns the interval during which job j can start as the next
+// dispatched job in state s. The job cannot be dispatched next if the
+// returned interval is empty.
+func (sp *Space) startTimes(s *State, j comm.Job, tWc comm.Time) comm.Interval {
+	rt := sp.readyTimes(s, j)
+	earliestStart := comm.Maximum(rt.From(), s.Availability().From())
+
+	tHigh := sp.nextHigherPriorityJobReady(s, j)
+	// the job starts strictly before a higher-priority job is ready
+	latestStart := comm.MinUpper(comm.At(tWc), sp.timeModel.Before(tHigh))
+
+	sp.logger.Debug("  EST: ", earliestStart, " LST: ", latestStart, " t_high: ", tHigh)
+
+	return comm.Between(comm.At(earliestStart), latestStart)
+}
+
+func (sp *Space) dispatch(s *State, j comm.Job, tWc comm.Time) (successor, bool) {
+	startRange := sp.startTimes(s, j, tWc)
+	if startRange.Empty() {
+		// the job has no feasible start time
+		return successor{}, false
+	}
+
+	finishRange := startRange.Add(j.Cost)
+	return sp.schedule(s, j, startRange, finishRange), true
+}
+
+// successor is the state that follows the dispatch of a job.
+type successor struct {
+	job              comm.Job
+	startTime        comm.Interval
+	finishTime       comm.Interval
+	coreAvailability []comm.Interval
+	jobs             comm.JobBitSet
+	finishTimes      map[string]comm.Interval
+	earliestRelease  comm.Time
+}
+
+func (sp *Space) schedule(parentState *State, j comm.Job, startRange comm.Interval, finishRange comm.Interval) successor {
+	alreadyScheduled := parentState.ScheduledJobs.With(j.Index)
+
+	sp.logger.Debug("Dispatch job: ", j.Name)
+
+	// the job occupies the earliest available core; the other cores cannot
+	// be used for the next job before the dispatched job starts
+	earliest := []comm.Time{finishRange.Start}
+	latest := []comm.Time{finishRange.End}
+	for _, a := range parentState.CoreAvailability[1:] {
+		earliest = append(earliest, comm.Maximum(startRange.Start, a.Start))
+		latest = append(latest, comm.Maximum(startRange.Start, a.End))
+	}
+	coreAvailability := sortCoreAvailability(earliest, latest)
+
+	finishTimes := sp.nextFinishTimes(parentState, alreadyScheduled, j, finishRange)
+	earliestRelease := sp.earliestPossibleJobRelease(parentState, j)
+	return successor{
+		job:              j,
+		startTime:        startRange,
+		finishTime:       finishRange,
+		coreAvailability: coreAvailability,
+		jobs:             alreadyScheduled,
+		finishTimes:      finishTimes,
+		earliestRelease:  earliestRelease,
+	}
+}
+
+// addSuccessor adds successor succ of parentState to the states of its
+// depth, merging it into an existing state if possible.
+func (sp *Space) addSuccessor(parentState *State, succ successor) {
+	if sp.beNaive {
+		sp.makeState(succ.coreAvailability, succ.jobs, succ.finishTimes, succ.earliestRelease, parentState, succ.job, succ.startTime, succ.finishTime)
+	} else {
+		if !sp.tryToMerge(succ.coreAvailability, succ.jobs, succ.finishTimes, succ.earliestRelease, parentState, succ.job, succ.startTime, succ.finishTime) {
+			sp.makeState(succ.coreAvailability, succ.jobs, succ.finishTimes, succ.earliestRelease, parentState, succ.job, succ.startTime, succ.finishTime)
+		}
+	}
+
+	sp.checkDeadline(parentState, succ.job, succ.finishTime)
+	sp.updateFinishTimes(succ.job, succ.finishTime)
+}
+
+// deadEnd records a deadline miss for every job that cannot be dispatched
+// in state s, which has no successor.
+func (sp *Space) deadEnd(s *State) {
+	sp.logger.Warning("---> Dead end: ", s.GetName())
+	sp.deadlineMiss = true
+	for _, m := range comm.DeadEndMisses(sp.workload, s.ScheduledJobs, s.GetName()) {
+		sp.misses = append(sp.misses, m)
+		sp.updateFinishTimes(m.Job, m.FinishTime)
+	}
+}
+
+// checkDeadline records a deadline miss if job j, dispatched in state s, can
+// finish after its deadline.
+func (sp *Space) checkDeadline(s *State, j comm.Job, finishTime comm.Interval) {
+	if j.MayMissDeadline(finishTime) {
+		sp.logger.Warning("---> Deadline miss: ", j.Name)
+		sp.deadlineMiss = true
+		sp.misses = append(sp.misses, comm.MissedDeadline{Job: j, State: s.GetName(), FinishTime: finishTime})
+	}
+}
+
+// nextFinishTimes keeps the finish times that are still needed to compute
+// the ready times of undispatched successors.
+func (sp *Space) nextFinishTimes(parentState *State, scheduled comm.JobBitSet, j comm.Job, finishRange comm.Interval) map[string]comm.Interval {
+	finishTimes := make(map[string]comm.Interval)
+	for name, ft := range parentState.FinishTimes {
+		if sp.hasPendingSuccessor(scheduled, name) {
+			finishTimes[name] = ft
+		}
+	}
+	if sp.hasPendingSuccessor(scheduled, j.Name) {
+		finishTimes[j.Name] = finishRange
+	}
+	return finishTimes
+}
+
+func (sp *Space) hasPendingSuccessor(scheduled comm.JobBitSet, name string) bool {
+	for _, succ := range sp.successors[name] {
+		if !scheduled.Contains(succ) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sp *Space) earliestPossibleJobRelease(s *State, j comm.Job) comm.Time {
+	// Iterate over all incomplete jobs in state s
+	for _, jt := range sp.jobsByEarliestArrival {
+
+		if jt.Arrival.Start < s.EarliestPendingRelease {
+			continue
+		}
+
+		// skip if it is already dispatched
+		if isDispatched(s.ScheduledJobs, *jt) {
+			continue
+		}
+
+		// skip if it is the one we're ignoring
+		if j.SameJob(*jt) {
+			continue
+		}
+
+		// it's incomplete and not ignored => found the earliest
+		return jt.Arrival.Min()
+
+	}
+	return comm.Infinity()
+}
+
+func (sp *Space) tryToMerge(coreAvailability []comm.Interval, j comm.JobBitSet, finishTimes map[string]comm.Interval,
+	earliestReleasePending comm.Time, parentState *State, dispatchedJob comm.Job, startRange comm.Interval,
+	finishTime comm.Interval) bool {
+	newState := NewState(sp.statesIndex, coreAvailability, j, finishTimes, earliestReleasePending)
+	tempStates := sp.states.getStatesWithSameJobs(j)
+
+	for _, s := range tempStates {
+		if s.IsMergePossible(newState) {
+			s.Merge(newState)
+			sp.numEdges++
+			if !sp.noGraph {
+				sp.dag.UpdateVertexLabel(s.GetID(), s.GetLabel(sp.timeModel))
+				sp.dag.AddEdge(parentState.GetID(), s.GetID(), edgeLabel(dispatchedJob, startRange, finishTime, sp.timeModel))
+			}
+			return true
+
+		}
+
+	}
+	return false
+
+}
+
+func (sp *Space) updateFinishTimes(j comm.Job, finishTime comm.Interval) {
+	// update the finish time of the job
+
+	if _, ok := sp.rta[j.Name]; ok {
+		sp.rta[j.Name] = sp.rta[j.Name].Widen(finishTime)
+	} else {
+		sp.rta[j.Name] = finishTime
+	}
+}
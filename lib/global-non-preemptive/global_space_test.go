@@ -0,0 +1,55 @@
+package global_non_preemptive
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/analysistest"
+	"go-test/lib/comm"
+	"go-test/lib/uni-non-preemptive"
+	"reflect"
+	"testing"
+)
+
+// TestSingleCoreMatchesUniprocessor checks that the global analysis on one
+// core finds the response times of the uniprocessor analysis. With
+// precedence constraints, the global analysis only covers them: it derives
+// the ready time of a job from the finish times of its predecessors and
+// not from the availability of the core, so a lower-priority job can start
+// before a successor that is ready on a single core.
+func TestSingleCoreMatchesUniprocessor(t *testing.T) {
+	tests := []struct {
+		file  string
+		prec  string
+		exact bool
+	}{
+		{file: "example.csv", exact: true},
+		{file: "example2.csv", exact: true},
+		{file: "example3.csv", exact: true},
+		{file: "example4.csv", exact: true},
+		{file: "example4.csv", prec: "example4.prec.csv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file+" "+tt.prec, func(t *testing.T) {
+			opts := comm.AnalysisOptions{Cores: 1, Logger: verbose.New("test")}
+			global := NewSpace(analysistest.ReadWorkload(t, tt.file, tt.prec), opts).Explore()
+			uni := uni_non_preemptive.NewSpace(analysistest.ReadWorkload(t, tt.file, tt.prec), opts).Explore()
+			if tt.exact {
+				if global.Verdict() != uni.Verdict() {
+					t.Errorf("verdict %s, want %s", global.Verdict(), uni.Verdict())
+				}
+				if !reflect.DeepEqual(global.ResponseTimes, uni.ResponseTimes) {
+					t.Errorf("response times %v, want %v", global.ResponseTimes, uni.ResponseTimes)
+				}
+				return
+			}
+			if global.IsSchedulable() && !uni.IsSchedulable() {
+				t.Errorf("verdict %s, want %s", global.Verdict(), uni.Verdict())
+			}
+			for name, want := range uni.ResponseTimes {
+				got, ok := global.ResponseTimes[name]
+				if !ok || got.Start > want.Start || got.End < want.End {
+					t.Errorf("%s: %v does not cover %v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package global_non_preemptive
+
+import (
+	"fmt"
+	"go-test/lib/comm"
+	"sort"
+)
+
+type State struct {
+	Index uint
+	// CoreAvailability holds one availability interval per core, sorted so
+	// that the first entry belongs to the earliest available core.
+	CoreAvailability []comm.Interval
+	ScheduledJobs    comm.JobBitSet
+	// FinishTimes keeps the finish-time interval of every dispatched job that
+	// still has an undispatched successor.
+	FinishTimes            map[string]comm.Interval
+	EarliestPendingRelease comm.Time
+	ID                     string
+}
+
+// functions for state
+func NewState(index uint, coreAvailability []comm.Interval, j comm.JobBitSet, finishTimes map[string]comm.Interval,
+	earliestRelease comm.Time) *State {
+
+	return &State{
+		Index:                  index,
+		CoreAvailability:       coreAvailability,
+		ScheduledJobs:          j,
+		FinishTimes:            finishTimes,
+		EarliestPendingRelease: earliestRelease,
+	}
+}
+
+// NewInitialState returns the state in which all m cores are idle at time
+// zero and none of the n jobs is scheduled.
+func NewInitialState(index uint, m uint, n int) *State {
+	coreAvailability := make([]comm.Interval, m)
+	for i := range coreAvailability {
+		coreAvailability[i] = comm.Interval{Start: 0, End: 0}
+	}
+	return NewState(index, coreAvailability, comm.NewJobBitSet(n), make(map[string]comm.Interval), comm.Time(0))
+}
+
+// Availability returns the availability interval of the earliest available core.
+func (s *State) Availability() comm.Interval {
+	return s.CoreAvailability[0]
+}
+
+func (s *State) GetName() string {
+	return "S" + fmt.Sprint(s.Index)
+}
+
+func (s State) GetID() string {
+	return s.ID
+}
+
+func (s State) coreString(sep string, m comm.TimeModel) string {
+	var str string
+	for i, a := range s.CoreAvailability {
+		if i > 0 {
+			str += sep
+		}
+		str += a.Format(m)
+	}
+	return str
+}
+
+func (s State) String() string {
+	return s.GetName() + "\n" + s.coreString(", ", comm.TimeModel{}) + "\n{" + s.ScheduledJobs.String() + "}\n" + s.EarliestPendingRelease.String()
+}
+
+func (s State) GetLabel(m comm.TimeModel) string {
+	var t string
+	if s.EarliestPendingRelease == comm.Infinity() {
+		t = "\"" + s.GetName() + ":" + s.coreString("\\n", m) + "\\nER=" + "Inf" + "\""
+	} else {
+		t = "\"" + s.GetName() + ":" + s.coreString("\\n", m) + "\\nER=" + s.EarliestPendingRelease.Format(m) + "\""
+	}
+
+	return t
+}
+
+func (s State) IsMergePossible(other *State) bool {
+	// cannot merge without loss of accuracy if the
+	// availability intervals of any core do not overlap
+	for i, a := range s.CoreAvailability {
+		if !a.Intersects(other.CoreAvailability[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *State) Merge(other *State) {
+	for i := range s.CoreAvailability {
+		s.CoreAvailability[i] = s.CoreAvailability[i].Widen(other.CoreAvailability[i])
+	}
+	for name, ft := range other.FinishTimes {
+		if own, ok := s.FinishTimes[name]; ok {
+			s.FinishTimes[name] = own.Widen(ft)
+		} else {
+			s.FinishTimes[name] = ft
+		}
+	}
+}
+
+// sortCoreAvailability builds the sorted core availability intervals from
+// the independently sorted lower and upper bounds.
+func sortCoreAvailability(earliest []comm.Time, latest []comm.Time) []comm.Interval {
+	sort.Slice(earliest, func(i, j int) bool {
+		return earliest[i] < earliest[j]
+	})
+	sort.Slice(latest, func(i, j int) bool {
+		return latest[i] < latest[j]
+	})
+
+	coreAvailability := make([]comm.Interval, len(earliest))
+	for i := range coreAvailability {
+		coreAvailability[i] = comm.Interval{Start: earliest[i], End: latest[i]}
+	}
+	return coreAvailability
+}
+
+// functions for state storage
+
+// StateStorage holds the states that are yet to be explored. The states are
+// bucketed by depth, i.e., by their number of scheduled jobs, and indexed by
+// the hash of their scheduled jobs within a depth, so that the merge
+// candidates of a state are found without scanning all states.
+type StateStorage struct {
+	depths map[int]*depthBucket
+}
+
+type depthBucket struct {
+	// states in the order they were made
+	states []*State
+	byJobs map[uint64][]*State
+}
+
+func NewStateStorage() *StateStorage {
+	return &StateStorage{depths: make(map[int]*depthBucket)}
+}
+
+func (s *StateStorage) AddState(st *State) {
+	depth := st.ScheduledJobs.Len()
+	b, ok := s.depths[depth]
+	if !ok {
+		b = &depthBucket{byJobs: make(map[uint64][]*State)}
+		s.depths[depth] = b
+	}
+	b.states = append(b.states, st)
+	h := st.ScheduledJobs.Hash()
+	b.byJobs[h] = append(b.byJobs[h], st)
+}
+
+// PopDepth removes the states of the given depth from the storage and
+// returns them in the order they were made.
+func (s *StateStorage) PopDepth(depth int) []*State {
+	b, ok := s.depths[depth]
+	if !ok {
+		return nil
+	}
+	delete(s.depths, depth)
+	return b.states
+}
+
+func (s *StateStorage) String() string {
+	depths := make([]int, 0, len(s.depths))
+	for depth := range s.depths {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+
+	var str string
+	for _, depth := range depths {
+		for _, v := range s.depths[depth].states {
+			str += v.String() + "\n---------\n"
+		}
+	}
+	return str
+
+}
+
+func (s *StateStorage) getStatesWithSameJobs(jobs comm.JobBitSet) []*State {
+	b, ok := s.depths[jobs.Len()]
+	if !ok {
+		return nil
+	}
+	var partialStates []*State
+	for _, state := range b.byJobs[jobs.Hash()] {
+		if state.ScheduledJobs.Equal(jobs) {
+			partialStates = append(partialStates, state)
+		}
+	}
+	return partialStates
+}
@@ -10,36 +10,40 @@ type reductionSet struct {
 	jobsByLatestArrival     comm.JobSet
 	jobsByWCET              comm.JobSet
 	latestBusyTime          comm.Time
-	latestIdleTime          comm.Time
+	latestIdleTime          comm.Bound
 	latestStartTimes        map[string]comm.Time
 	maxPriority             comm.Time
 	numInterferingJobsAdded uint
 	availability            comm.Interval
+	timeModel               comm.TimeModel
 }
 
-func CreateReductionSet(s *State, eligibleSuccessors comm.JobSet) *reductionSet {
-	jobsByEarliestArrivalLocal := make(comm.JobSet, len(eligibleSuccessors))
-	jobsByLatestArrivalLocal := make(comm.JobSet, len(eligibleSuccessors))
+// CreateReductionSet returns the reduction set of the eligible successors of
+// s. The busy and idle times are bounded with the jobs of the workload, given
+// sorted by earliest and by latest arrival, which are not modified, in the
+// time model m.
+func CreateReductionSet(s *State, eligibleSuccessors comm.JobSet, jobsByEarliestArrival, jobsByLatestArrival comm.JobSet, m comm.TimeModel) *reductionSet {
+	jobsByEarliestArrivalLocal := make(comm.JobSet, len(jobsByEarliestArrival))
+	jobsByLatestArrivalLocal := make(comm.JobSet, len(jobsByLatestArrival))
 	jobsByWCETLocal := make(comm.JobSet, len(eligibleSuccessors))
 
-	copy(jobsByEarliestArrivalLocal, eligibleSuccessors)
-	copy(jobsByLatestArrivalLocal, eligibleSuccessors)
+	copy(jobsByEarliestArrivalLocal, jobsByEarliestArrival)
+	copy(jobsByLatestArrivalLocal, jobsByLatestArrival)
 	copy(jobsByWCETLocal, eligibleSuccessors)
 
-	jobsByEarliestArrival.SortByEarliestArrival()
-	jobsByLatestArrival.SortByLatestArrival()
 	jobsByWCETLocal.SortByWCET()
 
 	rs := &reductionSet{
 		jobs:                    eligibleSuccessors,
-		jobsByEarliestArrival:   jobsByEarliestArrival,
-		jobsByLatestArrival:     jobsByLatestArrival,
+		jobsByEarliestArrival:   jobsByEarliestArrivalLocal,
+		jobsByLatestArrival:     jobsByLatestArrivalLocal,
 		jobsByWCET:              jobsByWCETLocal,
 		latestBusyTime:          comm.Time(0),
-		latestIdleTime:          comm.Time(0),
+		latestIdleTime:          comm.At(0),
 		maxPriority:             comm.Time(0),
 		numInterferingJobsAdded: 0,
 		availability:            s.Availability,
+		timeModel:               m,
 	}
 
 	rs.setLatestBusyTime()
@@ -60,7 +64,7 @@ func (rs *reductionSet) setLatestBusyTime() {
 }
 
 func (rs *reductionSet) setLatestIdleTime() {
-	idleTime := comm.Time(-1)
+	idleTime := comm.At(-1)
 
 	var idleJob *comm.Job
 	var t comm.Time
@@ -110,7 +114,7 @@ func (rs *reductionSet) setLatestIdleTime() {
 		rs.latestIdleTime = idleTime
 		return
 	} else {
-		rs.latestIdleTime = idleJob.GetLatestArrival() - comm.Epsilon()
+		rs.latestIdleTime = rs.timeModel.Before(idleJob.GetLatestArrival())
 	}
 
 }
@@ -164,7 +168,9 @@ func (rs *reductionSet) computeSi(i *comm.Job, jobsByPrio map[string]comm.Time)
 	if blockingJob == nil {
 		blockingTime = comm.Time(0)
 	} else {
-		blockingTime = comm.Maximum(0, blockingJob.GetMaximalCost()-comm.Epsilon())
+		// the blocking job starts strictly before i, the closed bound
+		// of the dense time model is a safe approximation
+		blockingTime = comm.Maximum(0, rs.timeModel.Before(blockingJob.GetMaximalCost()).Time)
 	}
 	latestStartTime = comm.Maximum(rs.availability.Max(), i.GetLatestArrival()+blockingTime)
 
@@ -302,12 +308,12 @@ func (rs *reductionSet) GetLatestStartTimes() comm.Time {
 func (rs *reductionSet) GetLabel() string {
 	var label string
 	for _, j := range rs.jobs {
-		label += j.Name + "\\nDL=" + j.Deadline.String() + "\\n"
+		label += j.Name + "\\nDL=" + j.Deadline.Format(rs.timeModel) + "\\n"
 	}
 	return label
 }
 
-func (rs *reductionSet) CanInterfere(job comm.Job, scheduledJobs comm.JobSet) bool {
+func (rs *reductionSet) CanInterfere(job comm.Job, scheduledJobs comm.JobBitSet) bool {
 	if !jobSatisfiesPrecedenceConstraints(rs, job, scheduledJobs) {
 		return false
 	}
@@ -318,7 +324,7 @@ func (rs *reductionSet) CanInterfere(job comm.Job, scheduledJobs comm.JobSet) bo
 	}
 
 	// rx_min < delta_M
-	if job.GetEarliestArrival() <= rs.latestIdleTime {
+	if rs.latestIdleTime.Covers(job.GetEarliestArrival()) {
 		return true
 	}
 
@@ -339,31 +345,32 @@ func (rs *reductionSet) CanInterfere(job comm.Job, scheduledJobs comm.JobSet) bo
 	return false
 }
 
-func jobSatisfiesPrecedenceConstraints(rs *reductionSet, job comm.Job, scheduledJobs comm.JobSet) bool {
+// jobSatisfiesPrecedenceConstraints reports whether the predecessors of job
+// are scheduled or in the reduction set.
+func jobSatisfiesPrecedenceConstraints(rs *reductionSet, job comm.Job, scheduledJobs comm.JobBitSet) bool {
 	if len(job.GetPredecessors()) == 0 {
 		return true
 	}
 
-	scheduledUnionReductionSet := scheduledJobs
-	scheduledUnionReductionSet = append(scheduledUnionReductionSet, rs.GetJobs()...)
+	var reductionSetJobs []int
+	for _, j := range rs.GetJobs() {
+		reductionSetJobs = append(reductionSetJobs, j.Index)
+	}
+	scheduledUnionReductionSet := scheduledJobs.With(reductionSetJobs...)
 
-	condition1 := scheduledUnionReductionSet.ContainsByNames(job.GetPredecessors())
+	return scheduledUnionReductionSet.ContainsAll(job.PredecessorIndices)
+}
 
-	condition2 := true
+// hasPredecessorOf reports whether a job of the reduction set precedes job.
+func (rs *reductionSet) hasPredecessorOf(job comm.Job) bool {
 	for _, j := range rs.GetJobs() {
 		for _, p := range job.GetPredecessors() {
 			if p == j.Name {
-				condition2 = false
-				break
+				return true
 			}
 		}
-		if condition2 == false {
-			break
-		}
 	}
-
-	return condition1 && condition2
-
+	return false
 }
 
 // Returns the smallest WCET among the jobs with a lower priority than job
@@ -1,7 +1,6 @@
 package uni_non_preemptive_por
 
 import (
-	"fmt"
 	"github.com/lfkeitel/verbose"
 	"go-test/lib/comm"
 	"time"
@@ -9,168 +8,287 @@ import (
 
 type responseTimes map[string]comm.Interval
 
-var beNaive bool = false
-var dag *comm.DAG
-var states *StateStorage
+// Space is the schedule-abstraction graph of a job set. It holds all the data
+// of one exploration, so independent spaces can be explored concurrently.
+type Space struct {
+	beNaive bool
+	dag     *comm.DAG
+	states  *StateStorage
+
+	// noGraph skips the construction of the graph, so that only the
+	// states that are yet to be explored are kept.
+	noGraph  bool
+	numEdges uint
+
+	statesIndex     uint
+	currentJobCount int
+
+	startTime   time.Time
+	elapsedTime time.Duration
+
+	jobsByEarliestArrival comm.JobSet
+	jobsByLatestArrival   comm.JobSet
+	jobsByDeadline        comm.JobSet
+	jobsByPriority        comm.JobSet
+	workload              comm.JobSet
+
+	// successors of each job that are released after a delay
+	delayedSuccessors map[string][]*comm.Job
+
+	// response times
+	rta responseTimes
+
+	timeout   uint
+	maxDepth  uint
+	earlyExit bool
+	// threads is the number of goroutines that expand the states of a depth
+	threads int
+
+	aborted       bool
+	deadlineMiss  bool
+	timedOut      bool
+	depthExceeded bool
+	// misses are the dispatches after which a job can miss its deadline
+	misses []comm.MissedDeadline
+
+	// insertionPolicy is the idle-time insertion policy of the scheduler.
+	insertionPolicy comm.IIP
+	// jobIndices maps the job names to their indices, for the policy,
+	// which holds its own copies of the jobs
+	jobIndices map[string]int
+
+	// porReleaseOrder adds interfering jobs to reduction sets by release
+	// order instead of by priority.
+	porReleaseOrder bool
+
+	timeModel comm.TimeModel
+	logger    *verbose.Logger
+}
+
+// NewSpace prepares the exploration of job set w. The jobs are copied, so w
+// is never modified by the analysis.
+func NewSpace(w comm.JobSet, opts comm.AnalysisOptions) *Space {
+	sp := &Space{
+		beNaive:         opts.Naive,
+		workload:        w.Clone(),
+		rta:             make(responseTimes),
+		timeout:         opts.Timeout,
+		maxDepth:        opts.MaxDepth,
+		earlyExit:       opts.EarlyExit,
+		noGraph:         opts.NoGraph,
+		threads:         opts.Threads,
+		insertionPolicy: opts.IIP,
+		porReleaseOrder: !opts.PorPriorityOrder,
+		timeModel:       opts.TimeModel,
+		logger:          opts.Logger,
+	}
+	sp.workload.IndexJobs()
+	sp.jobIndices = make(map[string]int, len(sp.workload))
+	for _, j := range sp.workload {
+		sp.jobIndices[j.Name] = j.Index
+	}
+	if sp.insertionPolicy == nil {
+		sp.insertionPolicy = comm.NullIIP{}
+	}
+	if sp.logger == nil {
+		sp.logger = verbose.New("NP::Uni::POR")
+	}
 
-var statesIndex uint = 0
-var currentJobCount int = 0
+	return sp
+}
 
-var startTime time.Time
-var elapsedTime time.Duration
+// Explore builds the schedule-abstraction graph and returns the result of
+// the analysis.
+func (sp *Space) Explore() *comm.AnalysisResult {
+	sp.startTime = time.Now()
+	// Preprocess the job such that they release at or after their predecessors release
+	sp.workload.PreprocessJobs()
+	sp.explore()
+	sp.elapsedTime = time.Since(sp.startTime)
+
+	return &comm.AnalysisResult{
+		Workload:      sp.workload,
+		TimeModel:     sp.timeModel,
+		ResponseTimes: sp.rta,
+		DeadlineMiss:  sp.deadlineMiss,
+		Misses:        sp.misses,
+		Aborted:       sp.aborted,
+		TimedOut:      sp.timedOut,
+		DepthExceeded: sp.depthExceeded,
+		Statistics:    sp.statistics(),
+		Graph:         sp.dag,
+	}
+}
 
-var jobsByEarliestArrival comm.JobSet
-var jobsByLatestArrival comm.JobSet
-var jobsByDeadline comm.JobSet
-var jobsByPriority comm.JobSet
-var workload comm.JobSet
+func (sp *Space) explore() {
+	sp.jobsByEarliestArrival = make(comm.JobSet, len(sp.workload))
+	sp.jobsByLatestArrival = make(comm.JobSet, len(sp.workload))
+	sp.jobsByDeadline = make(comm.JobSet, len(sp.workload))
+	sp.jobsByPriority = make(comm.JobSet, len(sp.workload))
 
-// response times
-var rta responseTimes
+	copy(sp.jobsByEarliestArrival, sp.workload)
+	copy(sp.jobsByLatestArrival, sp.workload)
+	copy(sp.jobsByDeadline, sp.workload)
+	copy(sp.jobsByPriority, sp.workload)
 
-var aborted bool = false
-var deadlineMiss bool = false
+	sp.jobsByEarliestArrival.SortByEarliestArrival()
+	sp.jobsByLatestArrival.SortByLatestArrival()
+	sp.jobsByDeadline.SortByDeadline()
+	sp.jobsByPriority.SortByPriority()
 
-var PorReleaseOrder bool = true
+	sp.delayedSuccessors = make(map[string][]*comm.Job)
+	for _, j := range sp.workload {
+		for pred := range j.PredecessorDelays {
+			sp.delayedSuccessors[pred] = append(sp.delayedSuccessors[pred], j)
+		}
+	}
 
-var logger *verbose.Logger
+	sp.initialize()
 
-func ExploreNaively(w comm.JobSet, timeout uint, earlyExit bool, maxDepth uint, v *verbose.Logger) {
-	beNaive = true
-	logger = v
-	startTime = time.Now()
-	rta = make(responseTimes)
-	workload = w
-	// Preprocess the job such that they release at or after their predecessors release
-	workload.PreprocessJobs()
-	explore(workload, timeout, earlyExit, maxDepth)
-	elapsedTime = time.Since(startTime)
-}
+	for sp.currentJobCount < len(sp.workload) {
+		if sp.maxDepth > 0 && uint(sp.currentJobCount) >= sp.maxDepth {
+			sp.logger.Warning("---> Depth limit exceeded!")
+			sp.depthExceeded = true
+			sp.aborted = true
+			break
+		}
 
-func Explore(w comm.JobSet, timeout uint, earlyExit bool, maxDepth uint, v *verbose.Logger) {
-	logger = v
-	startTime = time.Now()
-	rta = make(responseTimes)
-	workload = w
-	// Preprocess the job such that they release at or after their predecessors release
-	workload.PreprocessJobs()
-	explore(workload, timeout, earlyExit, maxDepth)
-	elapsedTime = time.Since(startTime)
-}
-
-func explore(workload comm.JobSet, timeout uint, earlyExit bool, maxDepth uint) {
-	jobsByEarliestArrival = make(comm.JobSet, len(workload))
-	jobsByLatestArrival = make(comm.JobSet, len(workload))
-	jobsByDeadline = make(comm.JobSet, len(workload))
-	jobsByPriority = make(comm.JobSet, len(workload))
-
-	copy(jobsByEarliestArrival, workload)
-	copy(jobsByLatestArrival, workload)
-	copy(jobsByDeadline, workload)
-	copy(jobsByPriority, workload)
-
-	jobsByEarliestArrival.SortByEarliestArrival()
-	jobsByLatestArrival.SortByLatestArrival()
-	jobsByDeadline.SortByDeadline()
-	jobsByPriority.SortByPriority()
-
-	initialize()
-
-	for currentJobCount < len(workload) {
-		frontStates := getFrontStates()
-		for _, s := range frontStates {
-			logger.Debug("==========================================")
-			logger.Debug("Looking at: ", s.GetName())
-			foundJob := exploreState(s)
-			if !foundJob && len(s.ScheduledJobs) != len(workload) {
+		frontStates := sp.getFrontStates()
+		// the states of a depth are expanded concurrently, and their
+		// successors are added in order, as in a sequential exploration
+		successors := make([][]successor, len(frontStates))
+		comm.ParallelFor(len(frontStates), sp.threads, func(i int) {
+			if !sp.isTimedOut() {
+				successors[i] = sp.exploreState(frontStates[i])
+			}
+		})
+		for i, s := range frontStates {
+			if sp.isTimedOut() {
+				sp.logger.Warning("---> Timeout!")
+				sp.timedOut = true
+				sp.aborted = true
+				break
+			}
+
+			for _, succ := range successors[i] {
+				sp.addSuccessor(s, succ)
+			}
+			if len(sp.misses) > 0 && sp.earlyExit {
+				sp.aborted = true
+				break
+			}
+			foundJob := len(successors[i]) > 0
+			if !foundJob && s.ScheduledJobs.Len() != len(sp.workload) {
 				// out of options and we didn't schedule all jobs
-				deadlineMiss = true
+				sp.deadEnd(s)
 
-				if earlyExit {
-					aborted = true
+				if sp.earlyExit {
+					sp.aborted = true
 					break
 				}
 			}
 		}
-		if aborted {
-			logger.Warning("---> Aborted!")
+		if sp.aborted {
+			sp.logger.Warning("---> Aborted!")
 			break
 		}
 
-		currentJobCount++
+		sp.currentJobCount++
 	}
 
 }
 
-func exploreState(s *State) bool {
-	var foundJob bool = false
+func (sp *Space) isTimedOut() bool {
+	return sp.timeout > 0 && time.Since(sp.startTime) > time.Duration(sp.timeout)*time.Second
+}
+
+// exploreState returns the successors of state s. It does not modify the
+// space, so that several states can be explored concurrently.
+func (sp *Space) exploreState(s *State) []successor {
+	var successors []successor
+
+	sp.logger.Debug("==========================================")
+	sp.logger.Debug("Looking at: ", s.GetName())
 
 	ts_min := s.Availability.From()
 	rel_min := s.EarliestPendingRelease
-	t_l := comm.Maximum(nextEligibleJobReady(s), s.Availability.Until())
+	t_l := comm.Maximum(sp.nextEligibleJobReady(s), s.Availability.Until())
 
 	nextRange := comm.Interval{Start: comm.Minimum(ts_min, rel_min), End: t_l}
 
-	logger.Debug("ts_min: ", ts_min)
-	logger.Debug("rel_min: ", rel_min)
-	logger.Debug("t_l: ", t_l)
-	logger.Debug("Next range: ", nextRange.String())
+	sp.logger.Debug("ts_min: ", ts_min)
+	sp.logger.Debug("rel_min: ", rel_min)
+	sp.logger.Debug("t_l: ", t_l)
+	sp.logger.Debug("Next range: ", nextRange.String())
 
 	// Iterate over all incomplete jobs that are released no later than nextRange.End
 	var eligibleSuccessors comm.JobSet
-	for _, jt := range jobsByEarliestArrival {
-		if jt.Arrival.Start < s.EarliestPendingRelease {
-			continue
+	for _, jt := range sp.jobsByEarliestArrival {
+		if jt.GetEarliestArrival() > nextRange.Until() {
+			break
 		}
 
-		if isDispatched(s.ScheduledJobs, *jt) {
+		jt = sp.effective(s, jt)
+		if jt.Arrival.Start < s.EarliestPendingRelease || jt.GetEarliestArrival() > nextRange.Until() {
 			continue
 		}
 
-		if jt.GetEarliestArrival() > nextRange.Until() {
-			break
+		if isDispatched(s.ScheduledJobs, *jt) {
+			continue
 		}
 
-		logger.Debug("+ ", jt.Name)
-		if isEligibleSuccessor(s, *jt) {
-			logger.Debug("  --> can be next ")
+		sp.logger.Debug("+ ", jt.Name)
+		if sp.isEligibleSuccessor(s, *jt) {
+			sp.logger.Debug("  --> can be next ")
 			eligibleSuccessors = append(eligibleSuccessors, jt)
 		}
 	}
-	if len(eligibleSuccessors) > 1 {
-
-		rs := CreateReductionSet(s, eligibleSuccessors)
-		for true {
+	// the reduction-set rules assume a work-conserving scheduler
+	if len(eligibleSuccessors) > 1 && !sp.insertionPolicy.CanBlock() {
+
+		rs := CreateReductionSet(s, eligibleSuccessors, sp.jobsByEarliestArrival, sp.jobsByLatestArrival, sp.timeModel)
+		// a successor of a job of the reduction set can run between its
+		// jobs, which the bounds of the reduction set do not cover
+		interferingSuccessor := false
+		for !interferingSuccessor {
 			if rs.HasPotentialDeadlineMisses() {
-				logger.Debug("  --> has potential deadline misses")
+				sp.logger.Debug("  --> has potential deadline misses")
 				break
 			}
 
 			var interferingJobs comm.JobSet
-			for _, jt := range jobsByEarliestArrival {
-				if jt.GetEarliestArrival() < s.EarliestPendingRelease {
-					continue
+			for _, jt := range sp.jobsByEarliestArrival {
+				if jt.GetEarliestArrival() > rs.GetLatestBusyTime()-rs.GetMinWCET() {
+					break
 				}
 
-				if isDispatched(s.ScheduledJobs, *jt) {
+				jt = sp.effective(s, jt)
+				if jt.GetEarliestArrival() < s.EarliestPendingRelease || jt.GetEarliestArrival() > rs.GetLatestBusyTime()-rs.GetMinWCET() {
 					continue
 				}
 
-				if jt.GetEarliestArrival() > rs.GetLatestBusyTime()-rs.GetMinWCET() {
-					break
+				if isDispatched(s.ScheduledJobs, *jt) {
+					continue
 				}
 
 				if rs.CanInterfere(*jt, s.ScheduledJobs) {
-					logger.Debug("  --> interfering with ", jt.Name)
+					if rs.hasPredecessorOf(*jt) {
+						sp.logger.Debug("  --> interfering successor ", jt.Name)
+						interferingSuccessor = true
+						break
+					}
+					sp.logger.Debug("  --> interfering with ", jt.Name)
 					interferingJobs = append(interferingJobs, jt)
 				}
 			}
 
-			if interferingJobs.Empty() {
-				logger.Debug("  --> no interfering jobs")
+			if interferingSuccessor {
+				break
+			} else if interferingJobs.Empty() {
+				sp.logger.Debug("  --> no interfering jobs")
 				break
 			} else {
-				if PorReleaseOrder {
+				if sp.porReleaseOrder {
 					jx := interferingJobs.SelectJobByReleaseOrder()
 					rs.AddJob(jx)
 				} else {
@@ -180,157 +298,215 @@ func exploreState(s *State) bool {
 			}
 
 		}
-		if !rs.HasPotentialDeadlineMisses() {
-			logger.Debug("  --> Partial-order reduction is safe")
-			scheduleReductionSet(s, rs)
-			foundJob = true
-			return foundJob
+		if !interferingSuccessor && !rs.HasPotentialDeadlineMisses() {
+			sp.logger.Debug("  --> Partial-order reduction is safe")
+			return []successor{sp.scheduleReductionSet(s, rs)}
 		} else {
-			logger.Debug("  --> Partial-order reduction is unsafe")
+			sp.logger.Debug("  --> Partial-order reduction is unsafe")
 		}
 	}
 	for _, jt := range eligibleSuccessors {
-		schedule(s, *jt)
-		foundJob = true
+		successors = append(successors, sp.schedule(s, *jt))
 	}
 
-	return foundJob
+	return successors
 }
 
-func initialize() {
-	dag = comm.NewDAG()
-	states = NewStateStorage()
+func (sp *Space) initialize() {
+	if !sp.noGraph {
+		sp.dag = comm.NewDAG()
+	}
+	sp.states = NewStateStorage()
 
 	// make root state
-	s0 := NewState(statesIndex, comm.Interval{Start: 0, End: 0}, comm.JobSet{}, comm.Time(0))
+	s0 := NewState(sp.statesIndex, comm.Interval{Start: 0, End: 0}, comm.NewJobBitSet(len(sp.workload)), comm.Time(0), nil)
 
-	v1, _ := dag.AddVertex(s0.GetName(), s0.GetLabel())
-	s0.ID = v1
-	states.AddState(s0)
+	if !sp.noGraph {
+		v1, _ := sp.dag.AddVertex(s0.GetName(), s0.GetLabel(sp.timeModel))
+		s0.ID = v1
+	}
+	sp.states.AddState(s0)
 
-	statesIndex++
+	sp.statesIndex++
 
 }
 
-func makeState(finishTime comm.Interval, jobs comm.JobSet, earliestReleasePending comm.Time,
-	parentState *State, dispatchedJob comm.Job) {
+func (sp *Space) makeState(finishTime comm.Interval, jobs comm.JobBitSet, earliestReleasePending comm.Time,
+	releases map[string]comm.Interval, parentState *State, dispatchedJob comm.Job) {
 
-	s := NewState(statesIndex, finishTime, jobs, earliestReleasePending)
-	newStateID, _ := dag.AddVertex(s.GetName(), s.GetLabel())
-	s.ID = newStateID
+	s := NewState(sp.statesIndex, finishTime, jobs, earliestReleasePending, releases)
+	if !sp.noGraph {
+		newStateID, _ := sp.dag.AddVertex(s.GetName(), s.GetLabel(sp.timeModel))
+		s.ID = newStateID
+	}
 
-	states.AddState(s)
+	sp.states.AddState(s)
 
-	edgeLabel := dispatchedJob.Name + "\\nDL=" + fmt.Sprint(dispatchedJob.Deadline)
-	edgeLabel += "\\nES=" + fmt.Sprint(finishTime.Start-dispatchedJob.Cost.Start) + "\\nLS=" + fmt.Sprint(finishTime.End-dispatchedJob.Cost.End)
-	edgeLabel += "\\nEF=" + fmt.Sprint(finishTime.Start) + "\\nLF=" + fmt.Sprint(finishTime.End)
-	dag.AddEdge(parentState.GetID(), newStateID, edgeLabel)
-	statesIndex++
+	sp.numEdges++
+	if !sp.noGraph {
+		sp.dag.AddEdge(parentState.GetID(), s.GetID(), edgeLabel(dispatchedJob, finishTime, sp.timeModel))
+	}
+	sp.statesIndex++
 
-	logger.Debug("Make state: ", s.GetName())
-	logger.Debug("Availability: ", s.Availability.String())
-	logger.Debug("Earliest pending release: ", s.EarliestPendingRelease)
-	logger.Debug("Scheduled jobs: ", s.ScheduledJobs.AbstractString())
-	logger.Debug("----------------------------------------")
+	sp.logger.Debug("Make state: ", s.GetName())
+	sp.logger.Debug("Availability: ", s.Availability.String())
+	sp.logger.Debug("Earliest pending release: ", s.EarliestPendingRelease)
+	sp.logger.Debug("Scheduled jobs: ", s.ScheduledJobs.Jobs(sp.workload).AbstractString())
+	sp.logger.Debug("----------------------------------------")
 }
 
-func makeStateForReductionSet(finishTime comm.Interval, jobs comm.JobSet, earliestReleasePending comm.Time,
-	parentState *State, rs *reductionSet) {
+func edgeLabel(dispatchedJob comm.Job, finishTime comm.Interval, m comm.TimeModel) string {
+	label := dispatchedJob.Name + "\\nDL=" + dispatchedJob.Deadline.Format(m)
+	label += "\\nES=" + (finishTime.Start - dispatchedJob.Cost.Start).Format(m) + "\\nLS=" + (finishTime.End - dispatchedJob.Cost.End).Format(m)
+	label += "\\nEF=" + finishTime.Start.Format(m) + "\\nLF=" + finishTime.Upper().Format(m)
+	return label
+}
 
-	s := NewState(statesIndex, finishTime, jobs, earliestReleasePending)
-	newStateID, _ := dag.AddVertex(s.GetName(), s.GetLabel())
-	s.ID = newStateID
+func (sp *Space) makeStateForReductionSet(finishTime comm.Interval, jobs comm.JobBitSet, earliestReleasePending comm.Time,
+	releases map[string]comm.Interval, parentState *State, rs *reductionSet) {
 
-	states.AddState(s)
+	s := NewState(sp.statesIndex, finishTime, jobs, earliestReleasePending, releases)
+	if !sp.noGraph {
+		newStateID, _ := sp.dag.AddVertex(s.GetName(), s.GetLabel(sp.timeModel))
+		s.ID = newStateID
+	}
 
-	edgeLabel := rs.GetLabel()
-	edgeLabel += "\\nES=" + fmt.Sprint(rs.GetEarliestStartTime()) + "\\nLS=" + fmt.Sprint(rs.GetLatestStartTimes())
-	edgeLabel += "\\nEF=" + fmt.Sprint(finishTime.Start) + "\\nLF=" + fmt.Sprint(finishTime.End)
-	dag.AddEdge(parentState.GetID(), newStateID, edgeLabel)
-	statesIndex++
+	sp.states.AddState(s)
 
-	logger.Debug("Make state: ", s.GetName())
-	logger.Debug("Availability: ", s.Availability.String())
-	logger.Debug("Earliest pending release: ", s.EarliestPendingRelease)
-	logger.Debug("Scheduled jobs: ", s.ScheduledJobs.AbstractString())
-	logger.Debug("----------------------------------------")
+	sp.numEdges++
+	if !sp.noGraph {
+		sp.dag.AddEdge(parentState.GetID(), s.GetID(), reductionSetEdgeLabel(rs, finishTime))
+	}
+	sp.statesIndex++
+
+	sp.logger.Debug("Make state: ", s.GetName())
+	sp.logger.Debug("Availability: ", s.Availability.String())
+	sp.logger.Debug("Earliest pending release: ", s.EarliestPendingRelease)
+	sp.logger.Debug("Scheduled jobs: ", s.ScheduledJobs.Jobs(sp.workload).AbstractString())
+	sp.logger.Debug("----------------------------------------")
 }
 
-func getFrontStates() []*State {
-	leaves := dag.GetLeaves()
-	var frontStates []*State
-	for _, leaf := range leaves {
-		s := states.GetState(fmt.Sprint(leaf))
-		frontStates = append(frontStates, s)
+func reductionSetEdgeLabel(rs *reductionSet, finishTime comm.Interval) string {
+	label := rs.GetLabel()
+	label += "\\nES=" + rs.GetEarliestStartTime().Format(rs.timeModel) + "\\nLS=" + rs.GetLatestStartTimes().Format(rs.timeModel)
+	label += "\\nEF=" + finishTime.Start.Format(rs.timeModel) + "\\nLF=" + finishTime.Upper().Format(rs.timeModel)
+	return label
+}
 
+func (sp *Space) statistics() comm.Statistics {
+	return comm.Statistics{
+		NumberOfStates: sp.statesIndex,
+		NumberOfEdges:  sp.numEdges,
+		Depth:          uint(sp.currentJobCount),
+		CPUTime:        sp.elapsedTime,
 	}
-	return frontStates
 }
 
-func nextEligibleJobReady(state *State) comm.Time {
+// getFrontStates returns the states at the current depth. They are removed
+// from the storage, as every state made from them is deeper.
+func (sp *Space) getFrontStates() []*State {
+	return sp.states.PopDepth(sp.currentJobCount)
+}
+
+func (sp *Space) nextEligibleJobReady(state *State) comm.Time {
 
+	// delayed jobs are released later than their position in the list
+	next := comm.Infinity()
 	alreadyScheduled := state.ScheduledJobs
-	for _, jt := range jobsByLatestArrival {
+	for _, jt := range sp.jobsByLatestArrival {
+		if jt.GetLatestArrival() >= next {
+			break
+		}
 
 		// not relevant if already scheduled
 		if isDispatched(alreadyScheduled, *jt) {
 			continue
 		}
 
+		// not relevant until its predecessors are dispatched
+		if !sp.ready(state, *jt) {
+			continue
+		}
+
+		jt = sp.effective(state, jt)
 		t := comm.Maximum(jt.GetLatestArrival(), state.Availability.Until())
 
-		// TODO: implement later
-		// if (iip_eligible(s, j, t)){
-		// 	continue
-		// }
+		if !sp.iipEligible(state, *jt, t) {
+			continue
+		}
 
-		if priorityEligible(state, *jt, t) {
-			return jt.GetLatestArrival()
+		if sp.priorityEligible(state, *jt, t) {
+			next = comm.Minimum(next, jt.GetLatestArrival())
 		}
 
 	}
-	return comm.Infinity()
+	return next
 
 }
 
-func isDispatched(jobs comm.JobSet, job comm.Job) bool {
-	for _, j := range jobs {
-		if j.Name == job.Name {
-			return true
-		}
+func isDispatched(jobs comm.JobBitSet, job comm.Job) bool {
+	return jobs.Contains(job.Index)
+}
+
+// effective returns job j with its release window in state s.
+func (sp *Space) effective(s *State, j *comm.Job) *comm.Job {
+	if r, ok := s.Releases[j.Name]; ok {
+		e := *j
+		e.Arrival = r
+		return &e
 	}
-	return false
+	return j
+}
+
+func (sp *Space) iipEligible(s *State, j comm.Job, t comm.Time) bool {
+	return comm.IIPEligible(sp.insertionPolicy, sp.policyView(s), j, t)
+}
+
+// policyView is the view of a state that is given to the idle-time insertion
+// policy. The jobs of the policy are looked up by name.
+type policyView struct {
+	s          *State
+	jobIndices map[string]int
 }
 
-func ready(state *State, job comm.Job) bool {
-	if !state.ScheduledJobs.ContainsByNames(job.GetPredecessors()) {
+func (sp *Space) policyView(s *State) policyView {
+	return policyView{s: s, jobIndices: sp.jobIndices}
+}
+
+func (v policyView) Incomplete(j comm.Job) bool {
+	return !v.s.ScheduledJobs.Contains(v.jobIndices[j.Name])
+}
+
+func (sp *Space) ready(state *State, job comm.Job) bool {
+	if !state.ScheduledJobs.ContainsAll(job.PredecessorIndices) {
 		return false
 	}
 
 	return true
 }
 
-func priorityEligible(s *State, j comm.Job, at comm.Time) bool {
-	return !certainlyReleasedHigherPriorityExists(s, j, at)
+func (sp *Space) priorityEligible(s *State, j comm.Job, at comm.Time) bool {
+	return !sp.certainlyReleasedHigherPriorityExists(s, j, at)
 }
 
-func certainlyReleasedHigherPriorityExists(s *State, j comm.Job, at comm.Time) bool {
+func (sp *Space) certainlyReleasedHigherPriorityExists(s *State, j comm.Job, at comm.Time) bool {
 	// ts_min := state.Availability.From()
 	// rel_min := state.EarliestPendingRelease
-	for _, jt := range jobsByLatestArrival {
+	for _, jt := range sp.jobsByLatestArrival {
 		// Iterare over all incomplete jobs that are certainly released no later than "at"
 
-		logger.Debug("        - considering ", jt.Name)
-		if jt.GetEarliestArrival() < s.EarliestPendingRelease {
-			//fmt.Println("        - 1")
-			continue
-		}
-
+		sp.logger.Debug("        - considering ", jt.Name)
 		if jt.GetLatestArrival() > at {
 			//fmt.Println("        - 2")
 			break
 		}
 
+		jt = sp.effective(s, jt)
+		if jt.GetEarliestArrival() < s.EarliestPendingRelease || jt.GetLatestArrival() > at {
+			//fmt.Println("        - 1")
+			continue
+		}
+
 		if isDispatched(s.ScheduledJobs, *jt) {
 			//fmt.Println("        - 3")
 			continue
@@ -343,13 +519,13 @@ func certainlyReleasedHigherPriorityExists(s *State, j comm.Job, at comm.Time) b
 		}
 
 		// ignore jobs that aren't yet ready
-		if !ready(s, j) {
+		if !sp.ready(s, *jt) {
 			continue
 		}
 
 		// check priority
 		if jt.HigherPriorityThan(j) {
-			logger.Debug("=> Found higher priority job: ", jt.Name)
+			sp.logger.Debug("=> Found higher priority job: ", jt.Name)
 			return true
 		}
 
@@ -358,78 +534,51 @@ func certainlyReleasedHigherPriorityExists(s *State, j comm.Job, at comm.Time) b
 
 }
 
-func scheduleEligibleSuccessors(s *State, nextRange comm.Interval) bool {
-	for _, jt := range jobsByEarliestArrival {
-
-		if jt.GetEarliestArrival() < s.EarliestPendingRelease {
-			continue
-		}
-
-		if jt.GetEarliestArrival() > nextRange.End {
-			continue
-		}
-
-		if isDispatched(s.ScheduledJobs, *jt) {
-			continue
-		}
-
-		if isEligibleSuccessor(s, *jt) {
-
-			schedule(s, *jt)
-
-			return true
-		}
-
-	}
-
-	return false
-}
-
-func isEligibleSuccessor(s *State, j comm.Job) bool {
+func (sp *Space) isEligibleSuccessor(s *State, j comm.Job) bool {
 
 	if isDispatched(s.ScheduledJobs, j) {
-		logger.Debug("Job ", j.Name, "   --> already complete")
+		sp.logger.Debug("Job ", j.Name, "   --> already complete")
 		return false
 	}
 
-	if !ready(s, j) {
+	if !sp.ready(s, j) {
 		return false
 	}
 
-	t_s := nextEarliestStartTime(s, j)
+	t_s := sp.nextEarliestStartTime(s, j)
 
-	if !priorityEligible(s, j, t_s) {
-		logger.Debug("Job ", j.Name, "   --> not priority eligible")
+	if !sp.priorityEligible(s, j, t_s) {
+		sp.logger.Debug("Job ", j.Name, "   --> not priority eligible")
 		return false
 	}
 
-	if !potentiallyNext(s, j) {
-		logger.Debug("Job ", j.Name, "   --> not potentially next")
+	if !sp.potentiallyNext(s, j) {
+		sp.logger.Debug("Job ", j.Name, "   --> not potentially next")
 		return false
 	}
 
-	// TODO: implement later
-	// if (!iip_eligible(s, j, t_s)) {
-	// 	return false
-	// }
+	if !sp.iipEligible(s, j, t_s) {
+		sp.logger.Debug("Job ", j.Name, "   --> not IIP eligible")
+		return false
+	}
 
 	return true
 
 }
 
-func nextEarliestStartTime(s *State, j comm.Job) comm.Time {
+func (sp *Space) nextEarliestStartTime(s *State, j comm.Job) comm.Time {
 	// t_S in paper, see definition 6.
 	return comm.Maximum(s.Availability.From(), j.GetEarliestArrival())
 }
 
-func potentiallyNext(s *State, j comm.Job) bool {
+func (sp *Space) potentiallyNext(s *State, j comm.Job) bool {
 	t_latest := s.Availability.Until()
 
 	// if t_latest >=  j.earliest_arrival(), then the
 	// job is trivially potentially next, so check the other case.
 
 	if t_latest < j.Arrival.Min() {
-		r := nextCertainJobRelease(s)
+		r := sp.nextCertainJobRelease(s)
 
 		// if something else is certainly released before j and IIP-
 		// eligible at the time of certain release, then j can't
@@ -443,12 +592,17 @@ func potentiallyNext(s *State, j comm.Job) bool {
 	return true
 }
 
-func nextCertainJobRelease(s *State) comm.Time {
+func (sp *Space) nextCertainJobRelease(s *State) comm.Time {
+	next := comm.Infinity()
 	alreadyScheduled := s.ScheduledJobs
 
-	for _, jt := range jobsByLatestArrival {
+	for _, jt := range sp.jobsByLatestArrival {
+		if jt.GetLatestArrival() >= next {
+			break
+		}
 
-		if jt.GetLatestArrival() < s.Availability.Min() {
+		jt = sp.effective(s, jt)
+		if jt.GetLatestArrival() < s.Availability.Min() || jt.GetLatestArrival() >= next {
 			continue
 		}
 
@@ -457,125 +611,228 @@ func nextCertainJobRelease(s *State) comm.Time {
 			continue
 		}
 
-		// TODO: implement later
+		// not relevant until its predecessors are dispatched
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
+		t := comm.Maximum(jt.GetLatestArrival(), s.Availability.Until())
+
 		// If the job is not IIP-eligible when it is certainly
 		// released, then there exists a schedule where it doesn't
 		// count, so skip it.
-
-		// if (!iip_eligible(s, j, std::max(j.latestArrival(), s.latest_finish_time())))
-		//                 continue;
+		if !sp.iipEligible(s, *jt, t) {
+			continue
+		}
 
 		// It must be priority-eligible when released, too.
 		// Relevant only if we have an IIP, otherwise the job is
 		// trivially priority-eligible.
-
-		// if (iip.can_block &&
-		// 	!priority_eligible(s, j, std::max(j.latestArrival(), s.latest_finish_time())))
-		// 	continue;
+		if sp.insertionPolicy.CanBlock() && !sp.priorityEligible(s, *jt, t) {
+			continue
+		}
 
 		// great, this job fits the bill
-		return jt.Arrival.End
+		next = jt.Arrival.End
 
 	}
-	return comm.Infinity()
+	return next
 
 }
 
-func schedule(parentState *State, j comm.Job) {
-	alreadyScheduled := make(comm.JobSet, len(parentState.ScheduledJobs))
-	copy(alreadyScheduled, parentState.ScheduledJobs)
-	finishRange := nextFinishTimes(parentState, j)
-
-	alreadyScheduled = append(alreadyScheduled, &j)
+// successor is the state that follows the dispatch of a job, or of a
+// reduction set if rs is set.
+type successor struct {
+	job             comm.Job
+	rs              *reductionSet
+	finishTime      comm.Interval
+	jobs            comm.JobBitSet
+	earliestRelease comm.Time
+	releases        map[string]comm.Interval
+}
 
-	logger.Debug("Dispatch job: ", j.Name)
+func (sp *Space) schedule(parentState *State, j comm.Job) successor {
+	alreadyScheduled := parentState.ScheduledJobs.With(j.Index)
+	finishRange := sp.nextFinishTimes(parentState, j)
 
-	if beNaive {
-		makeState(finishRange, alreadyScheduled, earliestPossibleJobRelease(parentState, j), parentState, j)
-	} else {
-		if !tryToMerge(finishRange, alreadyScheduled, earliestPossibleJobRelease(parentState, j), parentState, j) {
-			makeState(finishRange, alreadyScheduled, earliestPossibleJobRelease(parentState, j), parentState, j)
-		}
-	}
+	releases := sp.nextReleases(parentState, comm.JobSet{&j}, func(*comm.Job) comm.Interval { return finishRange })
 
-	updateFinishTimes(j, finishRange)
+	sp.logger.Debug("Dispatch job: ", j.Name)
 
+	earliestRelease := sp.earliestPossibleJobRelease(parentState, releases, j)
+	return successor{
+		job:             j,
+		finishTime:      finishRange,
+		jobs:            alreadyScheduled,
+		earliestRelease: earliestRelease,
+		releases:        releases,
+	}
 }
 
-func scheduleReductionSet(parentState *State, rs *reductionSet) {
-	alreadyScheduled := make(comm.JobSet, len(parentState.ScheduledJobs))
-	copy(alreadyScheduled, parentState.ScheduledJobs)
+func (sp *Space) scheduleReductionSet(parentState *State, rs *reductionSet) successor {
+	var dispatched []int
+	for _, j := range rs.GetJobs() {
+		dispatched = append(dispatched, j.Index)
+	}
+	alreadyScheduled := parentState.ScheduledJobs.With(dispatched...)
+
+	finishRange := sp.nextFinishTimesForReductionSet(rs)
+
+	releases := sp.nextReleases(parentState, rs.GetJobs(), func(j *comm.Job) comm.Interval {
+		return comm.Interval{Start: rs.getEarliestFinishTimeForJob(j), End: rs.getLatestFinishTimeForJob(j)}
+	})
+
+	sp.logger.Debug("++ Dispatch reduction set")
+	earliestRelease := sp.earliestPossibleJobReleaseForReductionSet(parentState, releases, rs)
+	return successor{
+		rs:              rs,
+		finishTime:      finishRange,
+		jobs:            alreadyScheduled,
+		earliestRelease: earliestRelease,
+		releases:        releases,
+	}
+}
 
-	finishRange := nextFinishTimesForReductionSet(rs)
+// addSuccessor adds successor succ of parentState to the states of its
+// depth, merging it into an existing state if possible.
+func (sp *Space) addSuccessor(parentState *State, succ successor) {
+	if succ.rs != nil {
+		if sp.beNaive {
+			sp.makeStateForReductionSet(succ.finishTime, succ.jobs, succ.earliestRelease, succ.releases, parentState, succ.rs)
+		} else {
+			if !sp.tryToMergeForReductionSet(succ.finishTime, succ.jobs, succ.earliestRelease, succ.releases, parentState, succ.rs) {
+				sp.makeStateForReductionSet(succ.finishTime, succ.jobs, succ.earliestRelease, succ.releases, parentState, succ.rs)
+			}
+		}
 
-	for _, j := range rs.GetJobs() {
-		alreadyScheduled = append(alreadyScheduled, j)
+		for _, j := range succ.rs.GetJobs() {
+			finishTime := comm.Interval{Start: succ.rs.getEarliestFinishTimeForJob(j), End: succ.rs.getLatestFinishTimeForJob(j)}
+			sp.checkDeadline(parentState, *j, finishTime)
+			sp.updateFinishTimes(*j, finishTime)
+		}
+		return
 	}
 
-	logger.Debug("++ Dispatch reduction set")
-	if beNaive {
-		makeStateForReductionSet(finishRange, alreadyScheduled, earliestPossibleJobReleaseForReductionSet(parentState, rs), parentState, rs)
+	if sp.beNaive {
+		sp.makeState(succ.finishTime, succ.jobs, succ.earliestRelease, succ.releases, parentState, succ.job)
 	} else {
-		if !tryToMergeForReductionSet(finishRange, alreadyScheduled, earliestPossibleJobReleaseForReductionSet(parentState, rs), parentState, rs) {
-			makeStateForReductionSet(finishRange, alreadyScheduled, earliestPossibleJobReleaseForReductionSet(parentState, rs), parentState, rs)
+		if !sp.tryToMerge(succ.finishTime, succ.jobs, succ.earliestRelease, succ.releases, parentState, succ.job) {
+			sp.makeState(succ.finishTime, succ.jobs, succ.earliestRelease, succ.releases, parentState, succ.job)
 		}
 	}
 
-	for _, j := range rs.GetJobs() {
-		updateFinishTimes(*j, comm.Interval{Start: rs.getEarliestFinishTimeForJob(j), End: rs.getLatestFinishTimeForJob(j)})
+	sp.checkDeadline(parentState, succ.job, succ.finishTime)
+	sp.updateFinishTimes(succ.job, succ.finishTime)
+}
+
+// deadEnd records a deadline miss for every job that cannot be dispatched
+// in state s, which has no successor.
+func (sp *Space) deadEnd(s *State) {
+	sp.logger.Warning("---> Dead end: ", s.GetName())
+	sp.deadlineMiss = true
+	for _, m := range comm.DeadEndMisses(sp.workload, s.ScheduledJobs, s.GetName()) {
+		sp.misses = append(sp.misses, m)
+		sp.updateFinishTimes(m.Job, m.FinishTime)
 	}
+}
 
+// checkDeadline records a deadline miss if job j, dispatched in state s, can
+// finish after its deadline.
+func (sp *Space) checkDeadline(s *State, j comm.Job, finishTime comm.Interval) {
+	if j.MayMissDeadline(finishTime) {
+		sp.logger.Warning("---> Deadline miss: ", j.Name)
+		sp.deadlineMiss = true
+		sp.misses = append(sp.misses, comm.MissedDeadline{Job: j, State: s.GetName(), FinishTime: finishTime})
+	}
 }
 
-func nextFinishTimesForReductionSet(rs *reductionSet) comm.Interval {
+// nextReleases returns the release windows of the successor state after
+// the given jobs have been dispatched. The successors of a dispatched job
+// with a delay are released once the delay after its completion is over.
+func (sp *Space) nextReleases(parentState *State, dispatched comm.JobSet, finishTime func(j *comm.Job) comm.Interval) map[string]comm.Interval {
+	var releases map[string]comm.Interval
+	for name, r := range parentState.Releases {
+		if !dispatched.ContainsByName(name) {
+			if releases == nil {
+				releases = make(map[string]comm.Interval)
+			}
+			releases[name] = r
+		}
+	}
+	for _, j := range dispatched {
+		for _, succ := range sp.delayedSuccessors[j.Name] {
+			if releases == nil {
+				releases = make(map[string]comm.Interval)
+			}
+			r, ok := releases[succ.Name]
+			if !ok {
+				r = parentState.Arrival(*succ)
+			}
+			ft := finishTime(j)
+			delay := succ.GetPredecessorDelay(j.Name)
+			releases[succ.Name] = comm.Interval{
+				Start: comm.Maximum(r.Start, ft.Start+delay.Start),
+				End:   comm.Maximum(r.End, ft.End+delay.End),
+			}
+		}
+	}
+	return releases
+}
+
+func (sp *Space) nextFinishTimesForReductionSet(rs *reductionSet) comm.Interval {
 	i := comm.Interval{Start: rs.GetEarliestFinishTime(), End: rs.GetLatestBusyTime()}
 	return i
 }
 
-func nextFinishTimes(s *State, j comm.Job) comm.Interval {
+func (sp *Space) nextFinishTimes(s *State, j comm.Job) comm.Interval {
 	// standard case -- this job is never aborted or skipped
-	i := comm.Interval{Start: nextEarliestFinishTime(s, j), End: nextLatestFinishTime(s, j)}
+	i := comm.Between(comm.At(sp.nextEarliestFinishTime(s, j)), sp.nextLatestFinishTime(s, j))
 
 	return i
 }
 
-func nextEarliestFinishTime(s *State, j comm.Job) comm.Time {
-	earliestStart := nextEarliestStartTime(s, j)
+func (sp *Space) nextEarliestFinishTime(s *State, j comm.Job) comm.Time {
+	earliestStart := sp.nextEarliestStartTime(s, j)
 
 	return comm.Time(earliestStart + j.Cost.Min())
 }
 
-func nextLatestFinishTime(s *State, j comm.Job) comm.Time {
-	otherCertainStart := nextCertainHigherPriorityJobRelease(s, j)
+// nextLatestFinishTime returns the upper bound of the finish time of j, which
+// is open if j must start strictly before a higher-priority job.
+func (sp *Space) nextLatestFinishTime(s *State, j comm.Job) comm.Bound {
+	otherCertainStart := sp.nextCertainHigherPriorityJobRelease(s, j)
 
-	// TODO: implement later
-	// t_s := nextEarliestStartTime(s, j)
-	// iip_latest_start := iip.latest_start(j, t_s, s);
+	t_s := sp.nextEarliestStartTime(s, j)
+	iipLatestStart := sp.insertionPolicy.LatestStart(j, t_s, sp.policyView(s))
 
 	// t_s'
 	// t_L
-	ownLatestStart := comm.Maximum(nextEligibleJobReady(s), s.Availability.Until())
+	ownLatestStart := comm.Maximum(sp.nextEligibleJobReady(s), s.Availability.Until())
 
-	logger.Debug("own latest start: ", ownLatestStart)
+	sp.logger.Debug("own latest start: ", ownLatestStart)
 
 	// t_R, t_I
-	// TODO: add iip_latest_start later
-	lastStartBeforeOther := comm.Minimum(otherCertainStart-comm.Epsilon(), comm.Infinity())
+	lastStartBeforeOther := comm.MinUpper(sp.timeModel.Before(otherCertainStart), comm.At(iipLatestStart))
 
-	logger.Debug("last start before other: ", lastStartBeforeOther)
+	sp.logger.Debug("last start before other: ", lastStartBeforeOther)
 
-	latestFinishTime := comm.Minimum(ownLatestStart, lastStartBeforeOther)
+	latestStart := comm.MinUpper(comm.At(ownLatestStart), lastStartBeforeOther)
 
-	return latestFinishTime + j.Cost.Max()
+	return latestStart.Add(j.Cost.Max())
 
 }
 
-func nextCertainHigherPriorityJobRelease(s *State, j comm.Job) comm.Time {
+func (sp *Space) nextCertainHigherPriorityJobRelease(s *State, j comm.Job) comm.Time {
+	next := comm.Infinity()
 	alreadyScheduled := s.ScheduledJobs
 
-	for _, jt := range jobsByLatestArrival {
+	for _, jt := range sp.jobsByLatestArrival {
+		if jt.Arrival.End >= next {
+			break
+		}
 
-		if jt.Arrival.End < s.Availability.Start {
+		jt = sp.effective(s, jt)
+		if jt.Arrival.End < s.Availability.Start || jt.Arrival.End >= next {
 			continue
 		}
 
@@ -584,23 +841,37 @@ func nextCertainHigherPriorityJobRelease(s *State, j comm.Job) comm.Time {
 			continue
 		}
 
+		// not relevant until its predecessors are dispatched
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
 		if !jt.HigherPriorityThan(j) {
 			continue
 		}
 
 		// great, this job fits the bill
 
-		return jt.Arrival.Max()
+		next = jt.Arrival.Max()
 
 	}
-	return comm.Infinity()
+	return next
 }
 
-func earliestPossibleJobRelease(s *State, j comm.Job) comm.Time {
+func (sp *Space) earliestPossibleJobRelease(s *State, releases map[string]comm.Interval, j comm.Job) comm.Time {
+	next := comm.Infinity()
 	// Iterate over all incomplete jobs in state s
-	for _, jt := range jobsByEarliestArrival {
+	for _, jt := range sp.jobsByEarliestArrival {
+		if jt.Arrival.Start >= next {
+			break
+		}
 
-		if jt.Arrival.Start < s.EarliestPendingRelease {
+		if r, ok := releases[jt.Name]; ok {
+			e := *jt
+			e.Arrival = r
+			jt = &e
+		}
+		if jt.Arrival.Start < s.EarliestPendingRelease || jt.Arrival.Start >= next {
 			continue
 		}
 
@@ -614,18 +885,27 @@ func earliestPossibleJobRelease(s *State, j comm.Job) comm.Time {
 			continue
 		}
 
-		// it's incomplete and not ignored => found the earliest
-		return jt.Arrival.Min()
+		// it's incomplete and not ignored => candidate for the earliest
+		next = jt.Arrival.Min()
 
 	}
-	return comm.Infinity()
+	return next
 }
 
-func earliestPossibleJobReleaseForReductionSet(s *State, rs *reductionSet) comm.Time {
+func (sp *Space) earliestPossibleJobReleaseForReductionSet(s *State, releases map[string]comm.Interval, rs *reductionSet) comm.Time {
+	next := comm.Infinity()
 	// Iterate over all incomplete jobs in state s
-	for _, jt := range jobsByEarliestArrival {
+	for _, jt := range sp.jobsByEarliestArrival {
+		if jt.Arrival.Start >= next {
+			break
+		}
 
-		if jt.Arrival.Start < s.EarliestPendingRelease {
+		if r, ok := releases[jt.Name]; ok {
+			e := *jt
+			e.Arrival = r
+			jt = &e
+		}
+		if jt.Arrival.Start < s.EarliestPendingRelease || jt.Arrival.Start >= next {
 			continue
 		}
 
@@ -639,26 +919,26 @@ func earliestPossibleJobReleaseForReductionSet(s *State, rs *reductionSet) comm.
 			continue
 		}
 
-		// it's incomplete and not ignored => found the earliest
-		return jt.Arrival.Min()
+		// it's incomplete and not ignored => candidate for the earliest
+		next = jt.Arrival.Min()
 
 	}
-	return comm.Infinity()
+	return next
 }
 
-func tryToMerge(finishTime comm.Interval, j comm.JobSet, earliestReleasePending comm.Time,
-	parentState *State, dispatchedJob comm.Job) bool {
-	newState := NewState(statesIndex, finishTime, j, earliestReleasePending)
-	tempStates := states.getStatesWithSameJobs(j)
-	edgeLabel := dispatchedJob.Name + "\\nDL=" + fmt.Sprint(dispatchedJob.Deadline)
-	edgeLabel += "\\nES=" + fmt.Sprint(finishTime.Start-dispatchedJob.Cost.Start) + "\\nLS=" + fmt.Sprint(finishTime.End-dispatchedJob.Cost.End)
-	edgeLabel += "\\nEF=" + fmt.Sprint(finishTime.Start) + "\\nLF=" + fmt.Sprint(finishTime.End)
+func (sp *Space) tryToMerge(finishTime comm.Interval, j comm.JobBitSet, earliestReleasePending comm.Time,
+	releases map[string]comm.Interval, parentState *State, dispatchedJob comm.Job) bool {
+	newState := NewState(sp.statesIndex, finishTime, j, earliestReleasePending, releases)
+	tempStates := sp.states.getStatesWithSameJobs(j)
 
 	for _, s := range tempStates {
 		if s.IsMergePossible(newState) {
 			s.Merge(newState)
-			dag.UpdateVertexLabel(s.GetID(), s.GetLabel())
-			dag.AddEdge(parentState.GetID(), s.GetID(), edgeLabel)
+			sp.numEdges++
+			if !sp.noGraph {
+				sp.dag.UpdateVertexLabel(s.GetID(), s.GetLabel(sp.timeModel))
+				sp.dag.AddEdge(parentState.GetID(), s.GetID(), edgeLabel(dispatchedJob, finishTime, sp.timeModel))
+			}
 			//logger.Debug("Successfully merged normal state ", s.GetID(), " with state ", newState.GetID())
 			return true
 
@@ -669,21 +949,20 @@ func tryToMerge(finishTime comm.Interval, j comm.JobSet, earliestReleasePending
 
 }
 
-func tryToMergeForReductionSet(finishTime comm.Interval, jobs comm.JobSet, earliestReleasePending comm.Time,
-	parentState *State, rs *reductionSet) bool {
+func (sp *Space) tryToMergeForReductionSet(finishTime comm.Interval, jobs comm.JobBitSet, earliestReleasePending comm.Time,
+	releases map[string]comm.Interval, parentState *State, rs *reductionSet) bool {
 
-	newState := NewState(statesIndex, finishTime, jobs, earliestReleasePending)
-	tempStates := states.getStatesWithSameJobs(jobs)
-
-	edgeLabel := rs.GetLabel()
-	edgeLabel += "\\nES=" + fmt.Sprint(rs.GetEarliestStartTime()) + "\\nLS=" + fmt.Sprint(rs.GetLatestStartTimes())
-	edgeLabel += "\\nEF=" + fmt.Sprint(finishTime.Start) + "\\nLF=" + fmt.Sprint(finishTime.End)
+	newState := NewState(sp.statesIndex, finishTime, jobs, earliestReleasePending, releases)
+	tempStates := sp.states.getStatesWithSameJobs(jobs)
 
 	for _, s := range tempStates {
 		if s.IsMergePossible(newState) {
 			s.Merge(newState)
-			dag.UpdateVertexLabel(s.GetID(), s.GetLabel())
-			dag.AddEdge(parentState.GetID(), s.GetID(), edgeLabel)
+			sp.numEdges++
+			if !sp.noGraph {
+				sp.dag.UpdateVertexLabel(s.GetID(), s.GetLabel(sp.timeModel))
+				sp.dag.AddEdge(parentState.GetID(), s.GetID(), reductionSetEdgeLabel(rs, finishTime))
+			}
 			return true
 
 		}
@@ -692,29 +971,12 @@ func tryToMergeForReductionSet(finishTime comm.Interval, jobs comm.JobSet, earli
 	return false
 }
 
-func updateFinishTimes(j comm.Job, finishTime comm.Interval) {
+func (sp *Space) updateFinishTimes(j comm.Job, finishTime comm.Interval) {
 	// update the finish time of the job
 
-	if _, ok := rta[j.Name]; ok {
-		rta[j.Name] = rta[j.Name].Widen(finishTime)
+	if _, ok := sp.rta[j.Name]; ok {
+		sp.rta[j.Name] = sp.rta[j.Name].Widen(finishTime)
 	} else {
-		rta[j.Name] = finishTime
-	}
-}
-
-func PrintResponseTimes() {
-	fmt.Println("Response times:")
-	fmt.Println("Name: I[BCCT,WCCT]")
-
-	for _, j := range workload {
-		fmt.Println(j.Name, ": ", rta[j.Name].String())
+		sp.rta[j.Name] = finishTime
 	}
 }
-
-func WriteResponseTimes(filePath string) {
-	comm.WriteResponseTimes(filePath, rta, workload)
-}
-
-func MakeDotFile(filePath string) {
-	dag.MakeDot(filePath)
-}
@@ -0,0 +1,54 @@
+package uni_non_preemptive_por
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/analysistest"
+	"go-test/lib/comm"
+	"go-test/lib/uni-non-preemptive"
+	"testing"
+)
+
+// TestReductionCoversExactAnalysis checks that the response times found with
+// partial-order reduction contain the response times of the exact analysis.
+// A reduction set is only safe if no successor of its jobs can run between
+// them.
+func TestReductionCoversExactAnalysis(t *testing.T) {
+	tests := []struct {
+		file  string
+		prec  string
+		tasks bool
+	}{
+		{file: "example4.csv"},
+		{file: "example4.csv", prec: "example4.prec.csv"},
+		{file: "example.csv"},
+		{file: "dagtask.yaml", tasks: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file+" "+tt.prec, func(t *testing.T) {
+			read := func() comm.JobSet {
+				if tt.tasks {
+					return analysistest.ReadTasks(t, tt.file)
+				}
+				return analysistest.ReadWorkload(t, tt.file, tt.prec)
+			}
+			opts := comm.AnalysisOptions{Logger: verbose.New("test")}
+			exact := uni_non_preemptive.NewSpace(read(), opts).Explore()
+			reduced := NewSpace(read(), opts).Explore()
+
+			if exact.DeadlineMiss {
+				t.Fatal("the exact analysis finds a deadline miss")
+			}
+			if reduced.DeadlineMiss {
+				t.Errorf("partial-order reduction finds a deadline miss (%v)", reduced.Misses)
+			}
+			for name, e := range exact.ResponseTimes {
+				r, ok := reduced.ResponseTimes[name]
+				if !ok {
+					t.Errorf("%s: no response time", name)
+				} else if r.Start > e.Start || r.End < e.End {
+					t.Errorf("%s: %v does not contain %v", name, r, e)
+				}
+			}
+		})
+	}
+}
@@ -3,26 +3,30 @@ package uni_non_preemptive
 import (
 	"fmt"
 	"go-test/lib/comm"
+	"sort"
 )
 
 type State struct {
 	Index                  uint
 	Availability           comm.Interval
-	ScheduledJobs          comm.JobSet
+	ScheduledJobs          comm.JobBitSet
 	EarliestPendingRelease comm.Time
-	ID                     string
+	// Releases are the release windows of the jobs with a delayed
+	// predecessor, shifted by the finish time of that predecessor.
+	Releases map[string]comm.Interval
+	ID       string
 }
 
-type StateStorage map[string]*State
-
 // functions for state
-func NewState(index uint, finishTime comm.Interval, j comm.JobSet, earliestRelease comm.Time) *State {
+func NewState(index uint, finishTime comm.Interval, j comm.JobBitSet, earliestRelease comm.Time,
+	releases map[string]comm.Interval) *State {
 
 	return &State{
 		Index:                  index,
 		Availability:           finishTime,
 		ScheduledJobs:          j,
 		EarliestPendingRelease: earliestRelease,
+		Releases:               releases,
 	}
 }
 
@@ -35,20 +39,33 @@ func (s State) GetID() string {
 }
 
 func (s State) String() string {
-	return s.GetName() + "\n" + s.Availability.String() + "\n{" + s.ScheduledJobs.AbstractString() + "}\n" + s.EarliestPendingRelease.String()
+	return s.GetName() + "\n" + s.Availability.String() + "\n{" + s.ScheduledJobs.String() + "}\n" + s.EarliestPendingRelease.String()
 }
 
-func (s State) GetLabel() string {
+func (s State) GetLabel(m comm.TimeModel) string {
 	var t string
 	if s.EarliestPendingRelease == comm.Infinity() {
-		t = "\"" + s.GetName() + ":" + fmt.Sprintf("I[%s,%s]", s.Availability.Start.String(), s.Availability.End.String()) + "\\nER=" + "Inf" + "\""
+		t = "\"" + s.GetName() + ":" + s.Availability.Format(m) + "\\nER=" + "Inf" + "\""
 	} else {
-		t = "\"" + s.GetName() + ":" + fmt.Sprintf("I[%s,%s]", s.Availability.Start.String(), s.Availability.End.String()) + "\\nER=" + fmt.Sprintf("%s", s.EarliestPendingRelease.String()) + "\""
+		t = "\"" + s.GetName() + ":" + s.Availability.Format(m) + "\\nER=" + s.EarliestPendingRelease.Format(m) + "\""
 	}
 
 	return t
 }
 
+// Arrival returns the release window of job j in state s.
+func (s *State) Arrival(j comm.Job) comm.Interval {
+	if r, ok := s.Releases[j.Name]; ok {
+		return r
+	}
+	return j.Arrival
+}
+
+// Incomplete reports whether job j has not been dispatched yet in state s.
+func (s *State) Incomplete(j comm.Job) bool {
+	return !isDispatched(s.ScheduledJobs, j)
+}
+
 func (s State) IsMergePossible(other *State) bool {
 	// cannot merge without loss of accuracy if the
 	// intervals do not overlap
@@ -61,41 +78,82 @@ func (s State) IsMergePossible(other *State) bool {
 
 func (s *State) Merge(other *State) {
 	(*s).Availability = s.Availability.Widen(other.Availability)
-
+	for name, r := range other.Releases {
+		s.Releases[name] = s.Releases[name].Widen(r)
+	}
+	// the release windows, and thus the earliest pending release, may
+	// differ between states with the same jobs
+	s.EarliestPendingRelease = comm.Minimum(s.EarliestPendingRelease, other.EarliestPendingRelease)
 }
 
 // functions for state storage
 
+// StateStorage holds the states that are yet to be explored. The states are
+// bucketed by depth, i.e., by their number of scheduled jobs, and indexed by
+// the hash of their scheduled jobs within a depth, so that the merge
+// candidates of a state are found without scanning all states.
+type StateStorage struct {
+	depths map[int]*depthBucket
+}
+
+type depthBucket struct {
+	// states in the order they were made
+	states []*State
+	byJobs map[uint64][]*State
+}
+
 func NewStateStorage() *StateStorage {
-	//return &StateStorage{}
-	t := make(StateStorage)
-	return &t
+	return &StateStorage{depths: make(map[int]*depthBucket)}
 }
 
 func (s *StateStorage) AddState(st *State) {
-	if _, exists := (*s)[(*st).GetName()]; exists {
-		logger.Fatal("Duplicate State!")
+	depth := st.ScheduledJobs.Len()
+	b, ok := s.depths[depth]
+	if !ok {
+		b = &depthBucket{byJobs: make(map[uint64][]*State)}
+		s.depths[depth] = b
 	}
-	(*s)[(*st).GetName()] = st
+	b.states = append(b.states, st)
+	h := st.ScheduledJobs.Hash()
+	b.byJobs[h] = append(b.byJobs[h], st)
 }
 
-func (s *StateStorage) GetState(name string) *State {
-	return (*s)[name]
+// PopDepth removes the states of the given depth from the storage and
+// returns them in the order they were made.
+func (s *StateStorage) PopDepth(depth int) []*State {
+	b, ok := s.depths[depth]
+	if !ok {
+		return nil
+	}
+	delete(s.depths, depth)
+	return b.states
 }
 
 func (s *StateStorage) String() string {
+	depths := make([]int, 0, len(s.depths))
+	for depth := range s.depths {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+
 	var str string
-	for _, v := range *s {
-		str += v.String() + "\n---------\n"
+	for _, depth := range depths {
+		for _, v := range s.depths[depth].states {
+			str += v.String() + "\n---------\n"
+		}
 	}
 	return str
 
 }
 
-func (s StateStorage) getStatesWithSameJobs(jobs comm.JobSet) []*State {
+func (s *StateStorage) getStatesWithSameJobs(jobs comm.JobBitSet) []*State {
+	b, ok := s.depths[jobs.Len()]
+	if !ok {
+		return nil
+	}
 	var partialStates []*State
-	for _, state := range s {
-		if state.ScheduledJobs.Compare(jobs) {
+	for _, state := range b.byJobs[jobs.Hash()] {
+		if state.ScheduledJobs.Equal(jobs) {
 			partialStates = append(partialStates, state)
 		}
 	}
@@ -0,0 +1,199 @@
+package uni_non_preemptive
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/analysistest"
+	"go-test/lib/comm"
+	"strings"
+	"testing"
+)
+
+func job(name string, task uint, arrival, cost comm.Interval, deadline, priority comm.Time) *comm.Job {
+	return &comm.Job{Name: name, TaskID: task, JobID: 1, Arrival: arrival, Cost: cost, Deadline: deadline, Priority: priority}
+}
+
+// TestWithoutHigherPriorityJob checks the latest finish time of jobs that no
+// higher-priority job can delay, where the next higher-priority release is
+// Infinity().
+func TestWithoutHigherPriorityJob(t *testing.T) {
+	tests := []struct {
+		name string
+		jobs comm.JobSet
+		iip  func(comm.JobSet) comm.IIP
+		want map[string]comm.Interval
+	}{
+		{
+			name: "single job",
+			jobs: comm.JobSet{job("J1", 1, comm.Interval{Start: 0, End: 3}, comm.Interval{Start: 1, End: 2}, 10, 1)},
+			want: map[string]comm.Interval{"J1": {Start: 1, End: 5}},
+		},
+		{
+			name: "highest priority last",
+			jobs: comm.JobSet{
+				job("J1", 1, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 2, End: 4}, 20, 2),
+				job("J2", 2, comm.Interval{Start: 1, End: 1}, comm.Interval{Start: 1, End: 1}, 20, 1),
+			},
+			want: map[string]comm.Interval{"J1": {Start: 2, End: 4}, "J2": {Start: 3, End: 5}},
+		},
+		{
+			name: "single job with P-RM",
+			jobs: comm.JobSet{job("J1", 1, comm.Interval{Start: 0, End: 3}, comm.Interval{Start: 1, End: 2}, 10, 1)},
+			iip: func(w comm.JobSet) comm.IIP {
+				return comm.NewPrecautiousRM(w)
+			},
+			want: map[string]comm.Interval{"J1": {Start: 1, End: 5}},
+		},
+		{
+			name: "single job with CW-EDF",
+			jobs: comm.JobSet{job("J1", 1, comm.Interval{Start: 0, End: 3}, comm.Interval{Start: 1, End: 2}, 10, 1)},
+			iip: func(w comm.JobSet) comm.IIP {
+				return comm.NewCriticalWindowEDF(w)
+			},
+			want: map[string]comm.Interval{"J1": {Start: 1, End: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload := tt.jobs
+			if err := workload.Validate(comm.TimeModel{}); err != nil {
+				t.Fatal(err)
+			}
+			opts := comm.AnalysisOptions{EarlyExit: true, Logger: verbose.New("test"), IIP: comm.NullIIP{}}
+			if tt.iip != nil {
+				opts.IIP = tt.iip(workload)
+			}
+			result := NewSpace(workload, opts).Explore()
+			if !result.IsSchedulable() {
+				t.Errorf("verdict %s, want schedulable", result.Verdict())
+			}
+			for name, want := range tt.want {
+				if got := result.ResponseTimes[name]; got != want {
+					t.Errorf("%s: %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestDeadEnd checks that every job that cannot be dispatched in a dead end
+// is reported as a miss that never finishes.
+func TestDeadEnd(t *testing.T) {
+	workload := comm.JobSet{
+		job("J1", 1, comm.Interval{Start: 3, End: 6}, comm.Interval{Start: 4, End: 5}, 16, 2),
+		job("J2", 2, comm.Interval{Start: 10, End: 11}, comm.Interval{Start: 4, End: 6}, 12, 4),
+		job("J3", 3, comm.Interval{Start: 8, End: 8}, comm.Interval{Start: 2, End: 4}, 13, 3),
+		job("J4", 4, comm.Interval{Start: 8, End: 11}, comm.Interval{Start: 5, End: 6}, 19, 3),
+	}
+	if err := workload.Validate(comm.TimeModel{}); err != nil {
+		t.Fatal(err)
+	}
+	opts := comm.AnalysisOptions{Logger: verbose.New("test"), IIP: comm.NewCriticalWindowEDF(workload)}
+	result := NewSpace(workload, opts).Explore()
+	if !result.DeadlineMiss {
+		t.Fatal("no deadline miss")
+	}
+	if len(result.Misses) != len(workload) {
+		t.Errorf("%d misses, want %d", len(result.Misses), len(workload))
+	}
+	for _, m := range result.Misses {
+		if m.FinishTime != comm.Never() {
+			t.Errorf("%s: finish time %v, want Never()", m.Job.Name, m.FinishTime)
+		}
+	}
+	if misses := result.JobMisses(); len(misses) != len(workload) {
+		t.Errorf("%d jobs in the miss report, want %d", len(misses), len(workload))
+	}
+}
+
+// TestPrecedenceDelay checks that a job is released no earlier than the
+// completion of its predecessor plus the delay between them.
+func TestPrecedenceDelay(t *testing.T) {
+	j1 := job("J1", 1, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 1, End: 2}, 20, 1)
+	j2 := job("J2", 2, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 1, End: 1}, 20, 2)
+	j2.AddPredecessorWithDelay("J1", comm.Interval{Start: 2, End: 3})
+	workload := comm.JobSet{j1, j2}
+	if err := workload.Validate(comm.TimeModel{}); err != nil {
+		t.Fatal(err)
+	}
+	result := NewSpace(workload, comm.AnalysisOptions{EarlyExit: true, Logger: verbose.New("test")}).Explore()
+	if !result.IsSchedulable() {
+		t.Fatalf("verdict %s, want schedulable", result.Verdict())
+	}
+	want := map[string]comm.Interval{"J1": {Start: 1, End: 2}, "J2": {Start: 4, End: 6}}
+	for name, w := range want {
+		if got := result.ResponseTimes[name]; got != w {
+			t.Errorf("%s: %v, want %v", name, got, w)
+		}
+	}
+}
+
+// TestLimitedPreemption checks that the high-priority job H preempts the
+// segmented job L at the boundary between its segments, at 2, and nowhere
+// else.
+func TestLimitedPreemption(t *testing.T) {
+	tests := []struct {
+		name    string
+		release comm.Time
+		limited bool
+		want    map[string]comm.Interval
+	}{
+		{name: "released in the first segment", release: 1, limited: true,
+			want: map[string]comm.Interval{"H": {Start: 3, End: 3}, "L": {Start: 5, End: 5}}},
+		{name: "released in the first segment, non-preemptive", release: 1,
+			want: map[string]comm.Interval{"H": {Start: 5, End: 5}, "L": {Start: 4, End: 4}}},
+		{name: "released in the last segment", release: 3, limited: true,
+			want: map[string]comm.Interval{"H": {Start: 5, End: 5}, "L": {Start: 4, End: 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := job("L", 1, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 4, End: 4}, 20, 2)
+			l.Segments = []comm.Interval{{Start: 2, End: 2}, {Start: 2, End: 2}}
+			workload := comm.JobSet{l, job("H", 2, comm.Interval{Start: tt.release, End: tt.release}, comm.Interval{Start: 1, End: 1}, 20, 1)}
+			if err := workload.Validate(comm.TimeModel{}); err != nil {
+				t.Fatal(err)
+			}
+			opts := comm.AnalysisOptions{EarlyExit: true, Logger: verbose.New("test"), LimitedPreemptive: tt.limited}
+			result := NewSpace(workload, opts).Explore()
+			if !result.IsSchedulable() {
+				t.Fatalf("verdict %s, want schedulable", result.Verdict())
+			}
+			for name, want := range tt.want {
+				if got := result.ResponseTimes[name]; got != want {
+					t.Errorf("%s: %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestSelfSuspension checks that the self-suspending job S releases the
+// processor during its suspension, in which the lower-priority job L runs,
+// and that its last segment is released 3 after its first segment
+// completes.
+func TestSelfSuspension(t *testing.T) {
+	s := job("S", 1, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 2, End: 2}, 10, 1)
+	s.Segments = []comm.Interval{{Start: 1, End: 1}, {Start: 1, End: 1}}
+	s.Suspensions = []comm.Interval{{Start: 3, End: 3}}
+	workload := comm.JobSet{s, job("L", 2, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 2, End: 2}, 10, 2)}
+	if err := workload.Validate(comm.TimeModel{}); err != nil {
+		t.Fatal(err)
+	}
+	result := NewSpace(workload, comm.AnalysisOptions{EarlyExit: true, Logger: verbose.New("test")}).Explore()
+	if !result.IsSchedulable() {
+		t.Fatalf("verdict %s, want schedulable", result.Verdict())
+	}
+	want := map[string]comm.Interval{"L": {Start: 3, End: 3}, "S": {Start: 5, End: 5}}
+	for name, w := range want {
+		if got := result.ResponseTimes[name]; got != w {
+			t.Errorf("%s: %v, want %v", name, got, w)
+		}
+	}
+	// L runs from the completion of the first segment of S, and the last
+	// segment of S starts when it is released, after L
+	dot := strings.Join(analysistest.DotLines(t, result), "\n")
+	for _, edge := range []string{`label="L\nDL=10\nES=1\nLS=1\n`, `label="S\nDL=10\nES=4\nLS=4\n`} {
+		if !strings.Contains(dot, edge) {
+			t.Errorf("no edge %s in\n%s", edge, dot)
+		}
+	}
+}
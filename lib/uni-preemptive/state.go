@@ -0,0 +1,251 @@
+package uni_preemptive
+
+import (
+	"fmt"
+	"go-test/lib/comm"
+	"hash/fnv"
+	"sort"
+)
+
+type State struct {
+	Index uint
+	// Availability is the interval in which the processor takes its next
+	// scheduling decision.
+	Availability comm.Interval
+	// ScheduledJobs are the jobs that have completed.
+	ScheduledJobs comm.JobBitSet
+	// PendingJobs are the jobs that are certainly released at the next
+	// scheduling decision but have not completed, e.g. preempted jobs, with
+	// their remaining execution time.
+	PendingJobs            map[string]comm.Interval
+	EarliestPendingRelease comm.Time
+	// Releases are the release windows of the jobs with a delayed
+	// predecessor, shifted by the finish time of that predecessor.
+	Releases map[string]comm.Interval
+	ID       string
+}
+
+// functions for state
+func NewState(index uint, availability comm.Interval, j comm.JobBitSet, pending map[string]comm.Interval,
+	earliestRelease comm.Time, releases map[string]comm.Interval) *State {
+
+	return &State{
+		Index:                  index,
+		Availability:           availability,
+		ScheduledJobs:          j,
+		PendingJobs:            pending,
+		EarliestPendingRelease: earliestRelease,
+		Releases:               releases,
+	}
+}
+
+func (s *State) GetName() string {
+	return "S" + fmt.Sprint(s.Index)
+}
+
+func (s State) GetID() string {
+	return s.ID
+}
+
+// pendingNames returns the names of the pending jobs in a stable order.
+func (s State) pendingNames() []string {
+	names := make([]string, 0, len(s.PendingJobs))
+	for name := range s.PendingJobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s State) pendingString(sep string, m comm.TimeModel) string {
+	var str string
+	for i, name := range s.pendingNames() {
+		if i > 0 {
+			str += sep
+		}
+		str += name + ":" + s.PendingJobs[name].Format(m)
+	}
+	return str
+}
+
+func (s State) String() string {
+	return s.GetName() + "\n" + s.Availability.String() + "\n{" + s.ScheduledJobs.String() + "}\n{" +
+		s.pendingString(", ", comm.TimeModel{}) + "}\n" + s.EarliestPendingRelease.String()
+}
+
+func (s State) GetLabel(m comm.TimeModel) string {
+	var t string
+	if s.EarliestPendingRelease == comm.Infinity() {
+		t = "\"" + s.GetName() + ":" + s.Availability.Format(m) + "\\nER=" + "Inf"
+	} else {
+		t = "\"" + s.GetName() + ":" + s.Availability.Format(m) + "\\nER=" + s.EarliestPendingRelease.Format(m)
+	}
+	if len(s.PendingJobs) > 0 {
+		t += "\\nP={" + s.pendingString("\\n", m) + "}"
+	}
+
+	return t + "\""
+}
+
+// Incomplete reports whether job j has not completed yet in state s.
+func (s *State) Incomplete(j comm.Job) bool {
+	return !isDispatched(s.ScheduledJobs, j)
+}
+
+// Arrival returns the release window of job j in state s.
+func (s *State) Arrival(j comm.Job) comm.Interval {
+	if r, ok := s.Releases[j.Name]; ok {
+		return r
+	}
+	return j.Arrival
+}
+
+// IsPending reports whether job j is certainly released but not completed
+// at the next scheduling decision of state s.
+func (s *State) IsPending(j comm.Job) bool {
+	_, ok := s.PendingJobs[j.Name]
+	return ok
+}
+
+// RemainingCost returns the remaining execution time of job j in state s.
+func (s *State) RemainingCost(j comm.Job) comm.Interval {
+	if rem, ok := s.PendingJobs[j.Name]; ok {
+		return rem
+	}
+	return j.Cost
+}
+
+// mergeHash hashes the completed and the pending jobs of s, which the states
+// that can be merged share.
+func (s State) mergeHash() uint64 {
+	h := s.ScheduledJobs.Hash()
+	for name := range s.PendingJobs {
+		// the sum does not depend on the order of the map
+		f := fnv.New64a()
+		f.Write([]byte(name))
+		h += f.Sum64()
+	}
+	return h
+}
+
+// sameJobs reports whether s and other have the same completed and the same
+// pending jobs.
+func (s State) sameJobs(other *State) bool {
+	if !s.ScheduledJobs.Equal(other.ScheduledJobs) || len(s.PendingJobs) != len(other.PendingJobs) {
+		return false
+	}
+	for name := range other.PendingJobs {
+		if _, ok := s.PendingJobs[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s State) IsMergePossible(other *State) bool {
+	// cannot merge without loss of accuracy if the
+	// intervals do not overlap
+	if !s.Availability.Intersects(other.Availability) {
+		return false
+	}
+
+	return true
+}
+
+func (s *State) Merge(other *State) {
+	(*s).Availability = s.Availability.Widen(other.Availability)
+	mergeIntervals(s.PendingJobs, other.PendingJobs)
+	mergeIntervals(s.Releases, other.Releases)
+	// the release windows, and thus the earliest pending release, may
+	// differ between states with the same jobs
+	s.EarliestPendingRelease = comm.Minimum(s.EarliestPendingRelease, other.EarliestPendingRelease)
+}
+
+// mergeIntervals widens the intervals of a with those of b for the jobs in
+// both maps, and adds the intervals of the jobs in b only.
+func mergeIntervals(a, b map[string]comm.Interval) {
+	for name, i := range b {
+		if m, ok := a[name]; ok {
+			a[name] = m.Widen(i)
+		} else {
+			a[name] = i
+		}
+	}
+}
+
+// functions for state storage
+
+// StateStorage holds the states that are yet to be explored. The states are
+// bucketed by depth, i.e., by the round of the exploration in which they
+// were made, as a preemption completes no job, and indexed by the hash of
+// their completed and pending jobs within a depth, so that the merge
+// candidates of a state are found without scanning all states.
+type StateStorage struct {
+	depths map[int]*depthBucket
+}
+
+type depthBucket struct {
+	// states in the order they were made
+	states []*State
+	byJobs map[uint64][]*State
+}
+
+func NewStateStorage() *StateStorage {
+	return &StateStorage{depths: make(map[int]*depthBucket)}
+}
+
+// AddState adds state st, made in the round depth, to the storage.
+func (s *StateStorage) AddState(st *State, depth int) {
+	b, ok := s.depths[depth]
+	if !ok {
+		b = &depthBucket{byJobs: make(map[uint64][]*State)}
+		s.depths[depth] = b
+	}
+	b.states = append(b.states, st)
+	h := st.mergeHash()
+	b.byJobs[h] = append(b.byJobs[h], st)
+}
+
+// PopDepth removes the states of the given depth from the storage and
+// returns them in the order they were made.
+func (s *StateStorage) PopDepth(depth int) []*State {
+	b, ok := s.depths[depth]
+	if !ok {
+		return nil
+	}
+	delete(s.depths, depth)
+	return b.states
+}
+
+func (s *StateStorage) String() string {
+	depths := make([]int, 0, len(s.depths))
+	for depth := range s.depths {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+
+	var str string
+	for _, depth := range depths {
+		for _, v := range s.depths[depth].states {
+			str += v.String() + "\n---------\n"
+		}
+	}
+	return str
+
+}
+
+// getStatesWithSameJobs returns the states of the given depth with the same
+// completed and pending jobs as st.
+func (s *StateStorage) getStatesWithSameJobs(st *State, depth int) []*State {
+	b, ok := s.depths[depth]
+	if !ok {
+		return nil
+	}
+	var partialStates []*State
+	for _, state := range b.byJobs[st.mergeHash()] {
+		if state.sameJobs(st) {
+			partialStates = append(partialStates, state)
+		}
+	}
+	return partialStates
+}
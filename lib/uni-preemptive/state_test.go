@@ -0,0 +1,54 @@
+package uni_preemptive
+
+import (
+	"go-test/lib/comm"
+	"reflect"
+	"testing"
+)
+
+func TestStateMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		s, other map[string]comm.Interval
+		want     map[string]comm.Interval
+	}{
+		{
+			name:  "in both states",
+			s:     map[string]comm.Interval{"J1": {Start: 2, End: 4}},
+			other: map[string]comm.Interval{"J1": {Start: 3, End: 7}},
+			want:  map[string]comm.Interval{"J1": {Start: 2, End: 7}},
+		},
+		{
+			name:  "in the other state only",
+			s:     map[string]comm.Interval{},
+			other: map[string]comm.Interval{"J1": {Start: 3, End: 7}},
+			want:  map[string]comm.Interval{"J1": {Start: 3, End: 7}},
+		},
+		{
+			name:  "in the merged state only",
+			s:     map[string]comm.Interval{"J1": {Start: 3, End: 7}},
+			other: map[string]comm.Interval{},
+			want:  map[string]comm.Interval{"J1": {Start: 3, End: 7}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copyOf := func(m map[string]comm.Interval) map[string]comm.Interval {
+				c := make(map[string]comm.Interval)
+				for k, v := range m {
+					c[k] = v
+				}
+				return c
+			}
+			s := NewState(1, comm.Interval{Start: 0, End: 5}, comm.NewJobBitSet(1), copyOf(tt.s), 0, copyOf(tt.s))
+			other := NewState(2, comm.Interval{Start: 1, End: 6}, comm.NewJobBitSet(1), copyOf(tt.other), 0, copyOf(tt.other))
+			s.Merge(other)
+			if !reflect.DeepEqual(s.PendingJobs, tt.want) {
+				t.Errorf("pending jobs %v, want %v", s.PendingJobs, tt.want)
+			}
+			if !reflect.DeepEqual(s.Releases, tt.want) {
+				t.Errorf("releases %v, want %v", s.Releases, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,822 @@
+package uni_preemptive
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/comm"
+	"time"
+)
+
+type responseTimes map[string]comm.Interval
+
+// Space is the schedule-abstraction graph of a job set under fully
+// preemptive job-level fixed-priority scheduling (e.g., FP or EDF with the
+// absolute deadline as priority). Every edge either completes a job or
+// preempts it in favour of a newly released higher-priority job.
+type Space struct {
+	beNaive bool
+	dag     *comm.DAG
+	states  *StateStorage
+
+	// noGraph skips the construction of the graph. Only the states of
+	// the current round and their successors are kept, and the edges are
+	// merely counted.
+	noGraph  bool
+	numEdges uint
+
+	statesIndex     uint
+	currentJobCount int
+
+	startTime   time.Time
+	elapsedTime time.Duration
+
+	jobsByEarliestArrival comm.JobSet
+	jobsByLatestArrival   comm.JobSet
+	jobsByName            map[string]*comm.Job
+	workload              comm.JobSet
+
+	// jobs is the analysed job set, while workload holds the dispatched
+	// jobs, i.e., the segment jobs of self-suspending jobs.
+	jobs comm.JobSet
+	// successors of each job that are released after a delay, e.g., a
+	// self-suspension
+	delayedSuccessors map[string][]*comm.Job
+
+	// response times
+	rta responseTimes
+
+	timeout   uint
+	maxDepth  uint
+	earlyExit bool
+
+	// threads is the number of goroutines that expand the states of a
+	// round
+	threads int
+
+	aborted       bool
+	deadlineMiss  bool
+	timedOut      bool
+	depthExceeded bool
+	// misses are the dispatches after which a job can miss its deadline
+	misses []comm.MissedDeadline
+
+	timeModel comm.TimeModel
+	logger    *verbose.Logger
+}
+
+// NewSpace prepares the exploration of job set w. The jobs are copied, so w
+// is never modified by the analysis. Idle-time insertion policies are not
+// supported by the preemptive analysis, so opts.IIP is ignored.
+func NewSpace(w comm.JobSet, opts comm.AnalysisOptions) *Space {
+	sp := &Space{
+		beNaive:   opts.Naive,
+		jobs:      w.Clone(),
+		rta:       make(responseTimes),
+		timeout:   opts.Timeout,
+		maxDepth:  opts.MaxDepth,
+		earlyExit: opts.EarlyExit,
+		noGraph:   opts.NoGraph,
+		threads:   opts.Threads,
+		timeModel: opts.TimeModel,
+		logger:    opts.Logger,
+	}
+	sp.workload = sp.jobs.SplitSegments(false)
+	sp.workload.IndexJobs()
+	if sp.logger == nil {
+		sp.logger = verbose.New("P::Uni")
+	}
+
+	return sp
+}
+
+// Explore builds the schedule-abstraction graph and returns the result of
+// the analysis.
+func (sp *Space) Explore() *comm.AnalysisResult {
+	sp.startTime = time.Now()
+	sp.explore()
+	sp.elapsedTime = time.Since(sp.startTime)
+
+	// the last segment of a job keeps the name of the job
+	rta := make(map[string]comm.Interval, len(sp.jobs))
+	for _, j := range sp.jobs {
+		if finishTime, ok := sp.rta[j.Name]; ok {
+			rta[j.Name] = finishTime
+		}
+	}
+
+	return &comm.AnalysisResult{
+		Workload:      sp.jobs,
+		TimeModel:     sp.timeModel,
+		ResponseTimes: rta,
+		DeadlineMiss:  sp.deadlineMiss,
+		Misses:        sp.misses,
+		Aborted:       sp.aborted,
+		TimedOut:      sp.timedOut,
+		DepthExceeded: sp.depthExceeded,
+		Statistics:    sp.statistics(),
+		Graph:         sp.dag,
+	}
+}
+
+func (sp *Space) explore() {
+	sp.jobsByEarliestArrival = make(comm.JobSet, len(sp.workload))
+	sp.jobsByLatestArrival = make(comm.JobSet, len(sp.workload))
+	sp.jobsByName = make(map[string]*comm.Job, len(sp.workload))
+
+	copy(sp.jobsByEarliestArrival, sp.workload)
+	copy(sp.jobsByLatestArrival, sp.workload)
+	sp.delayedSuccessors = make(map[string][]*comm.Job)
+	for _, j := range sp.workload {
+		sp.jobsByName[j.Name] = j
+		for pred := range j.PredecessorDelays {
+			sp.delayedSuccessors[pred] = append(sp.delayedSuccessors[pred], j)
+		}
+	}
+
+	sp.jobsByEarliestArrival.SortByEarliestArrival()
+	sp.jobsByLatestArrival.SortByLatestArrival()
+
+	sp.initialize()
+
+	// Each edge completes a job or turns a job pending, so the
+	// exploration ends after at most two rounds per job.
+	frontStates := sp.getFrontStates()
+	for len(frontStates) > 0 {
+		if sp.maxDepth > 0 && uint(sp.currentJobCount) >= sp.maxDepth {
+			sp.logger.Warning("---> Depth limit exceeded!")
+			sp.depthExceeded = true
+			sp.aborted = true
+			break
+		}
+
+		// the states of a round are expanded concurrently, and their
+		// successors are added in order, as in a sequential exploration
+		successors := make([][]successor, len(frontStates))
+		comm.ParallelFor(len(frontStates), sp.threads, func(i int) {
+			s := frontStates[i]
+			if !sp.isTimedOut() && s.ScheduledJobs.Len() != len(sp.workload) {
+				successors[i] = sp.exploreState(s)
+			}
+		})
+		for i, s := range frontStates {
+			if sp.isTimedOut() {
+				sp.logger.Warning("---> Timeout!")
+				sp.timedOut = true
+				sp.aborted = true
+				break
+			}
+
+			if s.ScheduledJobs.Len() == len(sp.workload) {
+				continue
+			}
+
+			for _, succ := range successors[i] {
+				sp.addSuccessor(s, succ)
+			}
+			if len(sp.misses) > 0 && sp.earlyExit {
+				sp.aborted = true
+				break
+			}
+			foundJob := len(successors[i]) > 0
+			if !foundJob {
+				// out of options and we didn't complete all jobs
+				sp.deadEnd(s)
+
+				if sp.earlyExit {
+					sp.aborted = true
+					break
+				}
+			}
+		}
+		if sp.aborted {
+			sp.logger.Warning("---> Aborted!")
+			break
+		}
+
+		// the successors of the states of a round are made in the next
+		// round
+		if frontStates = sp.states.PopDepth(sp.currentJobCount + 1); len(frontStates) > 0 {
+			sp.currentJobCount++
+		}
+	}
+
+}
+
+// getFrontStates returns the states of the current round. They are removed
+// from the storage, as every state made from them is made in a later round.
+func (sp *Space) getFrontStates() []*State {
+	return sp.states.PopDepth(sp.currentJobCount)
+}
+
+func (sp *Space) isTimedOut() bool {
+	return sp.timeout > 0 && time.Since(sp.startTime) > time.Duration(sp.timeout)*time.Second
+}
+
+// exploreState returns the successors of state s. It does not modify the
+// space, so that several states can be explored concurrently.
+func (sp *Space) exploreState(s *State) []successor {
+	var successors []successor
+
+	sp.logger.Debug("==========================================")
+	sp.logger.Debug("Looking at: ", s.GetName())
+
+	ts_min := s.Availability.From()
+	rel_min := s.EarliestPendingRelease
+	t_l := comm.Maximum(sp.nextEligibleJobReady(s), s.Availability.Until())
+
+	nextRange := comm.Interval{Start: comm.Minimum(ts_min, rel_min), End: t_l}
+
+	sp.logger.Debug("ts_min: ", ts_min)
+	sp.logger.Debug("rel_min: ", rel_min)
+	sp.logger.Debug("t_l: ", t_l)
+	sp.logger.Debug("Next range: ", nextRange.String())
+
+	// pending jobs are certainly released at the next decision
+	for _, name := range s.pendingNames() {
+		jt := sp.effective(s, sp.jobsByName[name])
+
+		sp.logger.Debug("+ ", jt.Name, " (pending)")
+		if sp.isEligibleSuccessor(s, *jt) {
+			sp.logger.Debug("  --> can be next ")
+			successors = append(successors, sp.dispatch(s, *jt)...)
+		}
+	}
+
+	// Iterate over all other incomplete jobs that are released no later than nextRange.End
+	for _, jt := range sp.jobsByEarliestArrival {
+		if jt.GetEarliestArrival() > nextRange.Until() {
+			break
+		}
+
+		if !s.Incomplete(*jt) || s.IsPending(*jt) {
+			continue
+		}
+
+		jt = sp.effective(s, jt)
+		if jt.Arrival.Start < s.EarliestPendingRelease || jt.GetEarliestArrival() > nextRange.Until() {
+			continue
+		}
+
+		sp.logger.Debug("+ ", jt.Name)
+		if sp.isEligibleSuccessor(s, *jt) {
+			sp.logger.Debug("  --> can be next ")
+			successors = append(successors, sp.dispatch(s, *jt)...)
+		}
+	}
+
+	return successors
+}
+
+func (sp *Space) initialize() {
+	sp.states = NewStateStorage()
+
+	// make root state
+	s0 := NewState(sp.statesIndex, comm.Interval{Start: 0, End: 0}, comm.NewJobBitSet(len(sp.workload)), map[string]comm.Interval{}, comm.Time(0), map[string]comm.Interval{})
+
+	if !sp.noGraph {
+		sp.dag = comm.NewDAG()
+		v1, _ := sp.dag.AddVertex(s0.GetName(), s0.GetLabel(sp.timeModel))
+		s0.ID = v1
+	}
+	sp.states.AddState(s0, 0)
+
+	sp.statesIndex++
+
+}
+
+// addSuccessor adds successor succ of parentState to the next round and
+// records the finish time of the job it completes, if any.
+func (sp *Space) addSuccessor(parentState *State, succ successor) {
+	sp.addState(succ.state, parentState, succ.edgeLabel)
+	if succ.completed {
+		sp.checkDeadline(parentState, succ.job, succ.state.Availability)
+		sp.updateFinishTimes(succ.job, succ.state.Availability)
+	}
+}
+
+// deadEnd records a deadline miss for every job that cannot be dispatched
+// in state s, which has no successor.
+func (sp *Space) deadEnd(s *State) {
+	sp.logger.Warning("---> Dead end: ", s.GetName())
+	sp.deadlineMiss = true
+	for _, m := range comm.DeadEndMisses(sp.workload, s.ScheduledJobs, s.GetName()) {
+		sp.misses = append(sp.misses, m)
+		sp.updateFinishTimes(m.Job, m.FinishTime)
+	}
+}
+
+// checkDeadline records a deadline miss if job j, which completes after
+// state s, can finish after its deadline.
+func (sp *Space) checkDeadline(s *State, j comm.Job, finishTime comm.Interval) {
+	if j.MayMissDeadline(finishTime) {
+		sp.logger.Warning("---> Deadline miss: ", j.Name)
+		sp.deadlineMiss = true
+		sp.misses = append(sp.misses, comm.MissedDeadline{Job: j, State: s.GetName(), FinishTime: finishTime})
+	}
+}
+
+// addState adds a successor of parentState to the next round, merging it
+// into an unexplored state with the same completed and pending jobs if
+// possible.
+func (sp *Space) addState(s *State, parentState *State, edgeLabel string) {
+	depth := sp.currentJobCount + 1
+	if !sp.beNaive {
+		for _, other := range sp.states.getStatesWithSameJobs(s, depth) {
+			if other.IsMergePossible(s) {
+				other.Merge(s)
+				if sp.noGraph {
+					sp.numEdges++
+				} else {
+					sp.dag.UpdateVertexLabel(other.GetID(), other.GetLabel(sp.timeModel))
+					sp.dag.AddEdge(parentState.GetID(), other.GetID(), edgeLabel)
+				}
+				return
+			}
+		}
+	}
+
+	s.Index = sp.statesIndex
+	if sp.noGraph {
+		sp.numEdges++
+	} else {
+		newStateID, _ := sp.dag.AddVertex(s.GetName(), s.GetLabel(sp.timeModel))
+		s.ID = newStateID
+
+		sp.dag.AddEdge(parentState.GetID(), newStateID, edgeLabel)
+	}
+	sp.states.AddState(s, depth)
+	sp.statesIndex++
+
+	sp.logger.Debug("Make state: ", s.GetName())
+	sp.logger.Debug("Availability: ", s.Availability.String())
+	sp.logger.Debug("Earliest pending release: ", s.EarliestPendingRelease)
+	sp.logger.Debug("Completed jobs: ", s.ScheduledJobs.Jobs(sp.workload).AbstractString())
+	sp.logger.Debug("Pending jobs: ", s.pendingString(", ", sp.timeModel))
+	sp.logger.Debug("----------------------------------------")
+}
+
+func (sp *Space) statistics() comm.Statistics {
+	stats := comm.Statistics{
+		NumberOfStates: sp.statesIndex,
+		NumberOfEdges:  sp.numEdges,
+		Depth:          uint(sp.currentJobCount),
+		CPUTime:        sp.elapsedTime,
+	}
+	if !sp.noGraph {
+		stats.NumberOfStates = uint(sp.dag.GetOrder())
+		stats.NumberOfEdges = uint(sp.dag.GetSize())
+	}
+	return stats
+}
+
+func isDispatched(jobs comm.JobBitSet, job comm.Job) bool {
+	return jobs.Contains(job.Index)
+}
+
+// effective returns job j with its release window in state s.
+func (sp *Space) effective(s *State, j *comm.Job) *comm.Job {
+	if r, ok := s.Releases[j.Name]; ok {
+		e := *j
+		e.Arrival = r
+		return &e
+	}
+	return j
+}
+
+// latestArrival returns the time at which job j is certainly released in
+// state s. Pending jobs are released before the next decision.
+func (sp *Space) latestArrival(s *State, j comm.Job) comm.Time {
+	if s.IsPending(j) {
+		return comm.Minimum(j.GetLatestArrival(), s.Availability.Min())
+	}
+	return j.GetLatestArrival()
+}
+
+func (sp *Space) nextEligibleJobReady(s *State) comm.Time {
+	next := comm.Infinity()
+
+	for _, name := range s.pendingNames() {
+		jt := sp.effective(s, sp.jobsByName[name])
+		t := comm.Maximum(sp.latestArrival(s, *jt), s.Availability.Until())
+
+		if sp.priorityEligible(s, *jt, t) {
+			next = comm.Minimum(next, sp.latestArrival(s, *jt))
+		}
+	}
+
+	for _, jt := range sp.jobsByLatestArrival {
+		if jt.GetLatestArrival() >= next {
+			break
+		}
+
+		// not relevant if already complete or handled above
+		if !s.Incomplete(*jt) || s.IsPending(*jt) {
+			continue
+		}
+
+		// not relevant until its predecessors are complete
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
+		jt = sp.effective(s, jt)
+		t := comm.Maximum(jt.GetLatestArrival(), s.Availability.Until())
+
+		if jt.GetLatestArrival() < next && sp.priorityEligible(s, *jt, t) {
+			next = jt.GetLatestArrival()
+		}
+
+	}
+	return next
+
+}
+
+func (sp *Space) ready(s *State, job comm.Job) bool {
+	if !s.ScheduledJobs.ContainsAll(job.PredecessorIndices) {
+		return false
+	}
+
+	return true
+}
+
+func (sp *Space) priorityEligible(s *State, j comm.Job, at comm.Time) bool {
+	return !sp.certainlyReleasedHigherPriorityExists(s, j, at)
+}
+
+func (sp *Space) certainlyReleasedHigherPriorityExists(s *State, j comm.Job, at comm.Time) bool {
+	for _, name := range s.pendingNames() {
+		jt := sp.effective(s, sp.jobsByName[name])
+
+		if jt.SameJob(j) || sp.latestArrival(s, *jt) > at {
+			continue
+		}
+
+		if jt.HigherPriorityThan(j) {
+			sp.logger.Debug("=> Found higher priority pending job: ", jt.Name)
+			return true
+		}
+	}
+
+	for _, jt := range sp.jobsByLatestArrival {
+		// Iterate over all incomplete jobs that are certainly released no later than "at"
+		if jt.GetLatestArrival() > at {
+			break
+		}
+
+		if !s.Incomplete(*jt) || s.IsPending(*jt) {
+			continue
+		}
+
+		jt = sp.effective(s, jt)
+		if jt.GetEarliestArrival() < s.EarliestPendingRelease || jt.GetLatestArrival() > at {
+			continue
+		}
+
+		// skip reference job
+		if jt.SameJob(j) {
+			continue
+		}
+
+		// ignore jobs that aren't yet ready
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
+		// check priority
+		if jt.HigherPriorityThan(j) {
+			sp.logger.Debug("=> Found higher priority job: ", jt.Name)
+			return true
+		}
+
+	}
+	return false
+
+}
+
+func (sp *Space) isEligibleSuccessor(s *State, j comm.Job) bool {
+
+	if !s.Incomplete(j) {
+		sp.logger.Debug("Job ", j.Name, "   --> already complete")
+		return false
+	}
+
+	if !sp.ready(s, j) {
+		sp.logger.Debug("Job ", j.Name, "   --> not ready")
+		return false
+	}
+
+	t_s := sp.nextEarliestStartTime(s, j)
+
+	if !sp.priorityEligible(s, j, t_s) {
+		sp.logger.Debug("Job ", j.Name, "   --> not priority eligible")
+		return false
+	}
+
+	if !sp.potentiallyNext(s, j) {
+		sp.logger.Debug("Job ", j.Name, "   --> not potentially next")
+		return false
+	}
+
+	return true
+
+}
+
+func (sp *Space) nextEarliestStartTime(s *State, j comm.Job) comm.Time {
+	return comm.Maximum(s.Availability.From(), j.GetEarliestArrival())
+}
+
+func (sp *Space) potentiallyNext(s *State, j comm.Job) bool {
+	t_latest := s.Availability.Until()
+
+	// if t_latest >=  j.earliest_arrival(), then the
+	// job is trivially potentially next, so check the other case.
+
+	if t_latest < j.Arrival.Min() {
+		r := sp.nextCertainJobRelease(s)
+
+		// if something else is certainly released before j, then j
+		// can't possibly be next
+		if r < j.Arrival.Min() {
+			return false
+		}
+
+	}
+	return true
+}
+
+func (sp *Space) nextCertainJobRelease(s *State) comm.Time {
+	// pending jobs are released at the latest when the processor
+	// becomes available
+	if len(s.PendingJobs) > 0 {
+		return s.Availability.Min()
+	}
+
+	next := comm.Infinity()
+	for _, jt := range sp.jobsByLatestArrival {
+		if jt.GetLatestArrival() >= next {
+			break
+		}
+
+		// not relevant if already complete
+		if !s.Incomplete(*jt) {
+			continue
+		}
+
+		// not relevant until its predecessors are complete
+		if !sp.ready(s, *jt) {
+			continue
+		}
+
+		jt = sp.effective(s, jt)
+		if jt.GetLatestArrival() < s.Availability.Min() {
+			continue
+		}
+
+		next = comm.Minimum(next, jt.Arrival.End)
+
+	}
+	return next
+
+}
+
+// nextLatestStartTime returns the upper bound of the times at which job j may
+// (re)start in state s, which is open if j must start strictly before a
+// higher-priority job.
+func (sp *Space) nextLatestStartTime(s *State, j comm.Job) comm.Bound {
+	otherCertainStart := sp.nextCertainHigherPriorityJobRelease(s, j)
+
+	// t_L
+	ownLatestStart := comm.Maximum(sp.nextEligibleJobReady(s), s.Availability.Until())
+
+	sp.logger.Debug("own latest start: ", ownLatestStart)
+
+	// t_R
+	lastStartBeforeOther := sp.timeModel.Before(otherCertainStart)
+
+	sp.logger.Debug("last start before other: ", lastStartBeforeOther)
+
+	latestStart := comm.MinUpper(comm.At(ownLatestStart), lastStartBeforeOther)
+	if est := sp.nextEarliestStartTime(s, j); !latestStart.Covers(est) {
+		return comm.At(est)
+	}
+	return latestStart
+}
+
+// nextCertainHigherPriorityJobRelease returns the earliest time at which a
+// ready higher-priority job is certainly released, i.e., the latest time at
+// which j is preempted if it is still running.
+func (sp *Space) nextCertainHigherPriorityJobRelease(s *State, j comm.Job) comm.Time {
+	next := comm.Infinity()
+	for _, jt := range sp.jobsByLatestArrival {
+		if jt.Arrival.End >= next {
+			break
+		}
+
+		if !s.Incomplete(*jt) || s.IsPending(*jt) {
+			continue
+		}
+
+		if !jt.HigherPriorityThan(j) || !sp.ready(s, *jt) {
+			continue
+		}
+
+		jt = sp.effective(s, jt)
+		if jt.Arrival.End < s.Availability.Start {
+			continue
+		}
+
+		// great, this job fits the bill
+		next = comm.Minimum(next, jt.Arrival.Max())
+
+	}
+	return next
+}
+
+// earliestPossibleJobRelease returns the earliest release of the incomplete
+// jobs of s that are neither pending nor excluded, given the release windows
+// of the successor state.
+func (sp *Space) earliestPossibleJobRelease(s *State, releases map[string]comm.Interval, excluded ...comm.Job) comm.Time {
+	next := comm.Infinity()
+	// Iterate over all incomplete jobs in state s
+	for _, jt := range sp.jobsByEarliestArrival {
+		if jt.Arrival.Start >= next {
+			break
+		}
+
+		if !s.Incomplete(*jt) || s.IsPending(*jt) {
+			continue
+		}
+
+		if r, ok := releases[jt.Name]; ok {
+			e := *jt
+			e.Arrival = r
+			jt = &e
+		}
+		if jt.Arrival.Start < s.EarliestPendingRelease {
+			continue
+		}
+
+		skip := false
+		for _, e := range excluded {
+			if e.SameJob(*jt) {
+				skip = true
+			}
+		}
+		if skip {
+			continue
+		}
+
+		next = comm.Minimum(next, jt.Arrival.Min())
+
+	}
+	return next
+}
+
+// successor is a state that follows parentState, either because a job
+// completes or because it is preempted.
+type successor struct {
+	job comm.Job
+	// completed is set if job completes in state, i.e., if the
+	// availability of state is the finish time of job
+	completed bool
+	state     *State
+	edgeLabel string
+}
+
+// dispatch returns the successors of parentState in which job j runs next:
+// one in which j completes and one for each higher-priority job that may
+// preempt it.
+func (sp *Space) dispatch(parentState *State, j comm.Job) []successor {
+	var successors []successor
+
+	rem := parentState.RemainingCost(j)
+	est := sp.nextEarliestStartTime(parentState, j)
+	lst := sp.nextLatestStartTime(parentState, j)
+	preemptBefore := sp.nextCertainHigherPriorityJobRelease(parentState, j)
+
+	sp.logger.Debug("Dispatch job: ", j.Name)
+
+	// j runs to completion
+	latestFinish := lst.Add(rem.Max())
+	finishRange := comm.Between(comm.At(est+rem.Min()), comm.MinUpper(latestFinish, comm.At(preemptBefore)))
+	if !finishRange.Empty() {
+		completed := parentState.ScheduledJobs.With(j.Index)
+
+		pending := make(map[string]comm.Interval, len(parentState.PendingJobs))
+		for name, r := range parentState.PendingJobs {
+			if name != j.Name {
+				pending[name] = r
+			}
+		}
+
+		// the successors of j with a delay, e.g., the segments that
+		// resume after a self-suspension, are released once the delay
+		// is over
+		releases := sp.copyReleases(parentState, j)
+		for _, succ := range sp.delayedSuccessors[j.Name] {
+			r := parentState.Arrival(*succ)
+			releases[succ.Name] = comm.Interval{
+				Start: comm.Maximum(r.Start, finishRange.Start+succ.GetPredecessorDelay(j.Name).Start),
+				End:   comm.Maximum(r.End, finishRange.End+succ.GetPredecessorDelay(j.Name).End),
+			}
+		}
+
+		s := NewState(0, finishRange, completed, pending, sp.earliestPossibleJobRelease(parentState, releases, j), releases)
+		successors = append(successors, successor{
+			job:       j,
+			completed: true,
+			state:     s,
+			edgeLabel: sp.edgeLabel(j, est, lst) + "\\nEF=" + finishRange.Start.Format(sp.timeModel) + "\\nLF=" + finishRange.Upper().Format(sp.timeModel),
+		})
+	}
+
+	// j is preempted by a higher-priority job released while it runs
+	for _, h := range sp.jobsByEarliestArrival {
+		if h.GetEarliestArrival() >= latestFinish.Time {
+			break
+		}
+
+		if h.SameJob(j) || !h.HigherPriorityThan(j) {
+			continue
+		}
+
+		if !parentState.Incomplete(*h) || parentState.IsPending(*h) || !sp.ready(parentState, *h) {
+			continue
+		}
+
+		h = sp.effective(parentState, h)
+		if h.GetEarliestArrival() >= latestFinish.Time {
+			continue
+		}
+
+		// j is preempted strictly after it starts and strictly before
+		// it completes
+		preemption := comm.Between(
+			comm.MaxLower(sp.timeModel.After(est), comm.At(h.GetEarliestArrival())),
+			comm.MinUpper(comm.At(preemptBefore), sp.timeModel.Before(latestFinish.Time)),
+		)
+		if preemption.Empty() {
+			continue
+		}
+
+		// j has executed for some time and not completed
+		executed := comm.Between(
+			comm.MaxLower(sp.timeModel.After(0), comm.Bound{Time: preemption.Start - lst.Time, Open: preemption.StartOpen || lst.Open}),
+			comm.Bound{Time: preemption.End - est, Open: preemption.EndOpen},
+		)
+		if rem.Max()-executed.Min() <= 0 {
+			continue
+		}
+		remaining := comm.Between(
+			comm.MaxLower(sp.timeModel.After(0), comm.Bound{Time: rem.Min() - executed.Max(), Open: executed.EndOpen}),
+			comm.Bound{Time: rem.Max() - executed.Min(), Open: executed.StartOpen},
+		)
+
+		pending := make(map[string]comm.Interval, len(parentState.PendingJobs)+2)
+		for name, r := range parentState.PendingJobs {
+			pending[name] = r
+		}
+		pending[j.Name] = remaining
+		pending[h.Name] = h.Cost
+
+		releases := sp.copyReleases(parentState)
+		s := NewState(0, preemption, parentState.ScheduledJobs, pending, sp.earliestPossibleJobRelease(parentState, releases, j, *h), releases)
+		successors = append(successors, successor{
+			job:       j,
+			state:     s,
+			edgeLabel: sp.edgeLabel(j, est, lst) + "\\nPB=" + h.Name + "\\nPT=" + preemption.Format(sp.timeModel) + "\\nRC=" + remaining.Format(sp.timeModel),
+		})
+	}
+
+	return successors
+}
+
+// copyReleases returns the release windows of s without those of the
+// completed job, if any.
+func (sp *Space) copyReleases(s *State, completed ...comm.Job) map[string]comm.Interval {
+	releases := make(map[string]comm.Interval, len(s.Releases))
+	for name, r := range s.Releases {
+		releases[name] = r
+	}
+	for _, j := range completed {
+		delete(releases, j.Name)
+	}
+	return releases
+}
+
+func (sp *Space) edgeLabel(j comm.Job, est comm.Time, lst comm.Bound) string {
+	return j.Name + "\\nDL=" + j.Deadline.Format(sp.timeModel) + "\\nES=" + est.Format(sp.timeModel) + "\\nLS=" + lst.Format(sp.timeModel)
+}
+
+func (sp *Space) updateFinishTimes(j comm.Job, finishTime comm.Interval) {
+	// update the finish time of the job
+	sp.logger.Debug("Finish time: ", finishTime)
+	sp.logger.Debug("old finish time: ", sp.rta[j.Name])
+
+	if _, ok := sp.rta[j.Name]; ok {
+		sp.rta[j.Name] = sp.rta[j.Name].Widen(finishTime)
+	} else {
+		sp.rta[j.Name] = finishTime
+	}
+	sp.logger.Debug("new finish time: ", sp.rta[j.Name])
+	sp.logger.Debug("------------------")
+}
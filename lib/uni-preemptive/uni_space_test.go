@@ -0,0 +1,83 @@
+package uni_preemptive
+
+import (
+	"github.com/lfkeitel/verbose"
+	"go-test/lib/analysistest"
+	"go-test/lib/comm"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func job(name string, task uint, arrival, cost comm.Interval, deadline, priority comm.Time) *comm.Job {
+	return &comm.Job{Name: name, TaskID: task, JobID: 1, Arrival: arrival, Cost: cost, Deadline: deadline, Priority: priority}
+}
+
+// explore validates workload and explores its graph.
+func explore(t *testing.T, workload comm.JobSet) *comm.AnalysisResult {
+	t.Helper()
+	if err := workload.Validate(comm.TimeModel{}); err != nil {
+		t.Fatal(err)
+	}
+	return NewSpace(workload, comm.AnalysisOptions{Logger: verbose.New("test")}).Explore()
+}
+
+// TestPreemption checks that the high-priority job H, released while the
+// low-priority job L runs, preempts L: L has executed for 1 or 2 time units
+// and resumes with the rest of its cost after H completes.
+func TestPreemption(t *testing.T) {
+	result := explore(t, comm.JobSet{
+		job("H", 1, comm.Interval{Start: 1, End: 2}, comm.Interval{Start: 1, End: 1}, 10, 1),
+		job("L", 2, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 4, End: 4}, 20, 2),
+	})
+
+	want := map[string]comm.Interval{"H": {Start: 2, End: 3}, "L": {Start: 4, End: 6}}
+	if !reflect.DeepEqual(result.ResponseTimes, want) {
+		t.Errorf("response times %v, want %v", result.ResponseTimes, want)
+	}
+
+	// L is certainly preempted, so it never completes without H
+	edges := []string{
+		`label="L\nDL=20\nES=0\nLS=0\nPB=H\nPT=I[1,2]\nRC=I[2,3]"`,
+		`label="H\nDL=10\nES=1\nLS=2\nEF=2\nLF=3"`,
+		`label="L\nDL=20\nES=2\nLS=3\nEF=4\nLF=6"`,
+	}
+	dot := strings.Join(analysistest.DotLines(t, result), "\n")
+	for _, edge := range edges {
+		if !strings.Contains(dot, edge) {
+			t.Errorf("no edge %s in\n%s", edge, dot)
+		}
+	}
+	if result.Statistics.NumberOfEdges != uint(len(edges)) {
+		t.Errorf("%d edges, want %d", result.Statistics.NumberOfEdges, len(edges))
+	}
+}
+
+// TestSelfSuspension checks that the self-suspending job S releases the
+// processor during its suspension, in which the lower-priority job L runs,
+// and that its last segment, released 3 after its first segment completes,
+// preempts L.
+func TestSelfSuspension(t *testing.T) {
+	s := job("S", 1, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 2, End: 2}, 10, 1)
+	s.Segments = []comm.Interval{{Start: 1, End: 1}, {Start: 1, End: 1}}
+	s.Suspensions = []comm.Interval{{Start: 3, End: 3}}
+	result := explore(t, comm.JobSet{s, job("L", 2, comm.Interval{Start: 0, End: 0}, comm.Interval{Start: 5, End: 5}, 10, 2)})
+
+	want := map[string]comm.Interval{"L": {Start: 7, End: 7}, "S": {Start: 5, End: 5}}
+	if !reflect.DeepEqual(result.ResponseTimes, want) {
+		t.Errorf("response times %v, want %v", result.ResponseTimes, want)
+	}
+
+	edges := []string{
+		`label="S#1\nDL=10\nES=0\nLS=0\nEF=1\nLF=1"`,
+		`label="L\nDL=10\nES=1\nLS=1\nPB=S\nPT=I[4,4]\nRC=I[2,2]"`,
+		`label="S\nDL=10\nES=4\nLS=4\nEF=5\nLF=5"`,
+		`label="L\nDL=10\nES=5\nLS=5\nEF=7\nLF=7"`,
+	}
+	dot := strings.Join(analysistest.DotLines(t, result), "\n")
+	for _, edge := range edges {
+		if !strings.Contains(dot, edge) {
+			t.Errorf("no edge %s in\n%s", edge, dot)
+		}
+	}
+}
@@ -1,18 +1,55 @@
 package main
 
 import (
+	"bufio"
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"github.com/docopt/docopt-go"
 	"github.com/lfkeitel/verbose"
 	"go-test/lib/comm"
+	global_non_preemptive "go-test/lib/global-non-preemptive"
 	uni_non_preemptive "go-test/lib/uni-non-preemptive"
 	uni_non_preemptive_por "go-test/lib/uni-non-preemptive-por"
+	uni_preemptive "go-test/lib/uni-preemptive"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// process exit codes
+const (
+	exitSchedulable  = 0
+	exitDeadlineMiss = 1
+	exitAborted      = 2
+	exitInputError   = 3
+)
+
+// analysis holds the options that select and configure the analysis of a
+// job set. It is also written to the json results.
+type analysis struct {
+	Name              string `json:"analysis"`
+	Cores             int    `json:"cores"`
+	Naive             bool   `json:"naive"`
+	Por               bool   `json:"por"`
+	Preemptive        bool   `json:"preemptive"`
+	LimitedPreemptive bool   `json:"limitedPreemptive"`
+	IIP               string `json:"iip"`
+	Timeout           int    `json:"timeout"`
+	DepthLimit        int    `json:"depthLimit"`
+	DenseTime         bool   `json:"denseTime"`
+	Resolution        string `json:"resolution"`
+	OutputUnit        string `json:"-"`
+	NoGraph           bool   `json:"noGraph"`
+	Threads           int    `json:"threads"`
+	ContinueAfterMiss bool   `json:"continueAfterMiss"`
+}
+
 func main() {
 
 	argUsage := `Unofficial implementation of schedule-abstraction graph analysis with GO
@@ -20,31 +57,97 @@ func main() {
 
 Usage:
 	main [-j FILE] [options]
+	main --tasks FILE [options]
+	main batch [options] <input>...
 	main -v
 	main -h
 
 Options:
-	-j FILE, --jobset FILE       jobset file [default: jobset.csv]
+	-j FILE, --jobset FILE       jobset file (-: stdin) [default: jobset.csv]
+	--format FORMAT              format of the jobset or task-set file (csv, yaml, json), instead of its extension
+	--tasks FILE                 periodic task-set file, expanded into a jobset
+	--horizon T                  expand the task set up to time T (0: one hyperperiod) [default: 0]
 	-e FILE, --precedence FILE   jobset's precedence file
 	-n, --naive                  use the naive exploration method [default: false]
 	-p, --por                    use the partial-order reduction [default: false]
+	--preemptive                 use the fully preemptive job model [default: false]
+	--limited-preemptive         allow preemptions between job segments only [default: false]
+	-m N, --multiprocessor N     number of identical processors [default: 1]
+	-i IIP, --iip IIP            idle-time insertion policy (none, p-rm, cw) [default: none]
+	-t SECONDS, --timeout SECONDS  stop the exploration after SECONDS (0: no limit) [default: 0]
+	-l N, --depth-limit N        stop the exploration at depth N (0: no limit) [default: 0]
 	-d, --dense-time             use dense time model [default: false]
+	--resolution UNIT            time unit of the analysis, to which times with a unit (ns, us, ms, s) are converted [default: ns]
+	--output-unit UNIT           unit of the times in the output files, instead of the time unit of the analysis
+	--no-graph                   compute the response times only, without building the graph [default: false]
+	--threads N                  number of goroutines that expand the states of one depth [default: 1]
+	--continue-after-miss        explore the whole graph past deadline misses and report every job that can miss [default: false]
 	-c, --csv                    store the best- and worst-case response times to csv file [default: false]
+	--json                       store the verdict, response times and statistics to json file [default: false]
+	-o FILE, --output FILE       store the response times to FILE (-: stdout), in json format with --json
+	--dot-output FILE            store the schedule-abstraction graph to FILE (-: stdout)
+	--miss-output FILE           store the deadline-miss report to FILE (-: stdout), in json format with --json
+	-w N, --workers N            number of job sets analysed in parallel in batch mode (0: one per CPU) [default: 0]
+	-s FILE, --summary FILE      batch summary csv file (-: stdout) [default: -]
 	-r N, --verbose N            print log messages (0-5) [default: 0]
 	-v, --version                show version and exit
 	-h, --help                   show this message
+
+In batch mode, each input is a directory, whose jobset files are analysed, a
+glob pattern, a manifest (.txt) listing one jobset file per line, or a jobset
+file. The precedence file of a jobset, e.g., example4.prec.csv for
+example4.csv, is read if it exists.
 `
 
-	arguments, _ := docopt.ParseArgs(argUsage, nil, "0.8.2")
+	// usage errors are input errors, not deadline misses
+	parser := &docopt.Parser{HelpHandler: func(err error, usage string) {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(exitInputError)
+		}
+		fmt.Println(usage)
+		os.Exit(exitSchedulable)
+	}}
+	arguments, _ := parser.ParseArgs(argUsage, nil, "0.8.2")
+	intArgument := func(name string) int {
+		n, err := arguments.Int(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s must be an integer\n", name)
+			parser.HelpHandler(err, usageSection(argUsage))
+		}
+		return n
+	}
 
 	//Parsing the command-line arguments
 	beNaive, _ := arguments.Bool("--naive")
 	por, _ := arguments.Bool("--por")
+	preemptive, _ := arguments.Bool("--preemptive")
+	limitedPreemptive, _ := arguments.Bool("--limited-preemptive")
 	inputFile, _ := arguments.String("--jobset")
 	precedenceFile, _ := arguments.String("--precedence")
-	verboseLevel, _ := arguments.Int("--verbose")
+	tasksFile, _ := arguments.String("--tasks")
+	horizonArg, _ := arguments.String("--horizon")
+	verboseLevel := intArgument("--verbose")
 	denseTime, _ := arguments.Bool("--dense-time")
+	resolution, _ := arguments.String("--resolution")
+	outputUnitName, _ := arguments.String("--output-unit")
+	noGraph, _ := arguments.Bool("--no-graph")
+	threads := intArgument("--threads")
+	continueAfterMiss, _ := arguments.Bool("--continue-after-miss")
 	wantCsv, _ := arguments.Bool("--csv")
+	wantJson, _ := arguments.Bool("--json")
+	inputFormat, _ := arguments.String("--format")
+	outputFile, _ := arguments.String("--output")
+	dotOutputFile, _ := arguments.String("--dot-output")
+	missOutputFile, _ := arguments.String("--miss-output")
+	numCores := intArgument("--multiprocessor")
+	iipName, _ := arguments.String("--iip")
+	timeout := intArgument("--timeout")
+	depthLimit := intArgument("--depth-limit")
+	batch, _ := arguments.Bool("batch")
+	batchInputs, _ := arguments["<input>"].([]string)
+	workers := intArgument("--workers")
+	summaryFile, _ := arguments.String("--summary")
 
 	commonLogger := verbose.New("Common")
 	sh := verbose.NewStdoutHandler(true)
@@ -63,87 +166,604 @@ Options:
 		sh.SetMinLevel(verbose.LogLevelDebug)
 	} else {
 		fmt.Println("Error: Invalid verbose level")
-		os.Exit(1)
+		os.Exit(exitInputError)
 	}
 
 	commonLogger.AddHandler("1", sh)
-	var workload comm.JobSet
+
+	// the report goes to stderr when the results are piped to stdout
+	report := os.Stdout
+	if outputFile == "-" || dotOutputFile == "-" || missOutputFile == "-" || batch && summaryFile == "-" {
+		report = os.Stderr
+	}
+
+	a := analysis{
+		Cores:             numCores,
+		Naive:             beNaive,
+		Por:               por,
+		Preemptive:        preemptive,
+		LimitedPreemptive: limitedPreemptive,
+		IIP:               iipName,
+		Timeout:           timeout,
+		DepthLimit:        depthLimit,
+		DenseTime:         denseTime,
+		Resolution:        resolution,
+		OutputUnit:        outputUnitName,
+		NoGraph:           noGraph,
+		Threads:           threads,
+		ContinueAfterMiss: continueAfterMiss,
+	}
+	a.Name = a.loggerName()
+
+	if err := a.check(); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(exitInputError)
+	}
+	if workers < 0 {
+		fmt.Println("Error: Invalid number of workers")
+		os.Exit(exitInputError)
+	}
+	stdoutFiles := 0
+	for _, f := range []string{outputFile, dotOutputFile, missOutputFile} {
+		if f == "-" {
+			stdoutFiles++
+		}
+	}
+	if stdoutFiles > 1 {
+		fmt.Println("Error: Only one of the response times, the graph and the deadline-miss report can be written to stdout")
+		os.Exit(exitInputError)
+	}
+	if noGraph && dotOutputFile != "" {
+		fmt.Println("Error: The graph cannot be written when it is not built")
+		os.Exit(exitInputError)
+	}
+	if report == os.Stderr && verboseLevel > 0 {
+		fmt.Println("Error: Log messages cannot be printed while writing to stdout")
+		os.Exit(exitInputError)
+	}
+	if outputFile != "" && wantCsv && wantJson {
+		fmt.Println("Error: The response times can be written to a single output file only")
+		os.Exit(exitInputError)
+	}
+
+	horizon, err := a.timeModel().ParseTime(horizonArg, comm.RoundUp)
+	if err != nil || horizon < 0 {
+		fmt.Println("Error: Invalid horizon")
+		os.Exit(exitInputError)
+	}
+
+	logger := verbose.New(a.Name)
+	logger.AddHandler("1", sh)
+
+	if batch {
+		files, err := findJobSets(batchInputs)
+		if err != nil {
+			commonLogger.Critical("Error: ", err)
+			os.Exit(exitInputError)
+		}
+		results := runBatch(files, a, workers, commonLogger, logger)
+		if err := writeSummary(summaryFile, results); err != nil {
+			commonLogger.Critical("Error: ", err)
+			os.Exit(exitInputError)
+		}
+		printBatchReport(report, results)
+		os.Exit(batchExitStatus(results))
+	}
+
 	var csvOutputFile string
+	var jsonOutputFile string
 
 	//read job set
-	fileExtension := filepath.Ext(inputFile)
-	if fileExtension == ".csv" {
-		workload = comm.ReadJobSet(inputFile, commonLogger)
-	} else if fileExtension == ".yaml" {
-		workload = comm.ReadJobSetYAML(inputFile, commonLogger)
+	if tasksFile != "" {
+		// the outputs are named after the task set
+		inputFile = tasksFile
+	}
+	if inputFormat == "" && inputFile == "-" {
+		fmt.Println("Error: The input format must be given with --format when reading from stdin")
+		os.Exit(exitInputError)
+	}
+	workload, err := readWorkload(inputFile, inputFormat, tasksFile != "", horizon, precedenceFile, a.timeModel(), commonLogger)
+	if err != nil {
+		commonLogger.Critical("Error: ", err)
+		os.Exit(exitInputError)
+	}
+
+	// the outputs are named after the input file, unless given explicitly
+	outputBase := strings.TrimSuffix(inputFile, filepath.Ext(inputFile))
+	if inputFile == "-" && (wantCsv || wantJson) && outputFile == "" {
+		fmt.Println("Error: The output file must be given with --output when reading from stdin")
+		os.Exit(exitInputError)
+	}
+	if outputFile != "" {
+		if wantJson {
+			jsonOutputFile = outputFile
+		} else {
+			wantCsv = true
+			csvOutputFile = outputFile
+		}
+	} else {
+		if wantCsv {
+			csvOutputFile = outputBase + ".rta.csv"
+		}
+		if wantJson {
+			jsonOutputFile = outputBase + ".rta.json"
+		}
+	}
+	if dotOutputFile == "" && inputFile != "-" && !noGraph {
+		dotOutputFile = outputBase + ".dot"
+	}
+	// the deadline-miss report is written whenever every miss is searched
+	if continueAfterMiss && missOutputFile == "" {
+		if inputFile == "-" {
+			fmt.Println("Error: The deadline-miss report must be given with --miss-output when reading from stdin")
+			os.Exit(exitInputError)
+		}
+		if wantJson {
+			missOutputFile = outputBase + ".misses.json"
+		} else {
+			missOutputFile = outputBase + ".misses.csv"
+		}
+	}
+
+	start := time.Now()
+	result, err := a.run(workload, logger)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(exitInputError)
+	}
+
+	result.FprintResponseTimes(report)
+	var outputErrors []error
+	if dotOutputFile != "" {
+		outputErrors = append(outputErrors, result.WriteDotFile(dotOutputFile))
+	}
+	if wantCsv {
+		outputErrors = append(outputErrors, result.WriteResponseTimes(csvOutputFile))
+	}
+	if wantJson {
+		outputErrors = append(outputErrors, result.WriteJSON(jsonOutputFile, a))
+	}
+	if missOutputFile != "" {
+		if wantJson {
+			outputErrors = append(outputErrors, result.WriteMissesJSON(missOutputFile))
+		} else {
+			outputErrors = append(outputErrors, result.WriteMisses(missOutputFile))
+		}
+	}
+	for _, err := range outputErrors {
+		if err != nil {
+			commonLogger.Critical("Error: ", err)
+			os.Exit(exitInputError)
+		}
+	}
+
+	fmt.Fprintln(report, "Time elapsed: ", time.Since(start))
+	fmt.Fprintln(report, "Statistics:", result.Stats())
+	if continueAfterMiss {
+		for _, t := range result.TaskMisses() {
+			fmt.Fprintf(report, "Task %d: %d of %d jobs can miss their deadline, by up to %s\n", t.TaskID, t.Misses, t.Jobs, t.MaxOvershoot.OutputString(result.TimeModel))
+		}
+	} else {
+		for _, miss := range result.Misses {
+			fmt.Fprintln(report, "Deadline miss:", miss.Format(result.TimeModel))
+		}
+	}
+
+	fmt.Fprintln(report, "Verdict:", result.Verdict())
+	os.Exit(exitStatus(result))
+}
+
+// exitStatus returns the exit code of the verdict of result.
+func exitStatus(result *comm.AnalysisResult) int {
+	if result.TimedOut || result.DepthExceeded {
+		return exitAborted
+	} else if !result.IsSchedulable() {
+		return exitDeadlineMiss
+	}
+	return exitSchedulable
+}
+
+// usageSection returns the usage patterns of doc, which docopt prints on a
+// usage error.
+func usageSection(doc string) string {
+	usage := doc[strings.Index(doc, "Usage:"):]
+	return strings.TrimSpace(usage[:strings.Index(usage, "\n\n")])
+}
+
+// check reports invalid combinations of options.
+func (a analysis) check() error {
+	if a.Cores < 1 {
+		return errors.New("Invalid number of processors")
+	}
+	if a.Timeout < 0 || a.DepthLimit < 0 {
+		return errors.New("Invalid timeout or depth limit")
+	}
+	if a.Threads < 1 {
+		return errors.New("Invalid number of threads")
+	}
+	if a.Cores > 1 && a.Por {
+		return errors.New("Partial-order reduction is only supported on a single processor")
+	}
+	if a.Preemptive && (a.Cores > 1 || a.Por) {
+		return errors.New("The preemptive analysis supports neither multiple processors nor partial-order reduction")
+	}
+	if a.LimitedPreemptive && (a.Cores > 1 || a.Por || a.Preemptive) {
+		return errors.New("The limited-preemptive analysis is only supported by the non-preemptive uniprocessor analysis")
+	}
+	if _, err := comm.ParseUnit(a.Resolution); err != nil {
+		return errors.New("Invalid time resolution")
+	}
+	if _, err := comm.ParseUnit(a.OutputUnit); a.OutputUnit != "" && err != nil {
+		return errors.New("Invalid output unit")
+	}
+	switch a.IIP {
+	case "none", "p-rm", "cw":
+	default:
+		return errors.New("Invalid idle-time insertion policy")
+	}
+	if (a.Cores > 1 || a.Preemptive || a.LimitedPreemptive) && a.IIP != "none" {
+		return errors.New("Idle-time insertion policies are only supported by the non-preemptive uniprocessor analysis")
+	}
+	return nil
+}
+
+func (a analysis) loggerName() string {
+	loggerName := "NP::Uni"
+	if a.Cores > 1 {
+		loggerName = "NP::Global"
+	} else if a.Preemptive {
+		loggerName = "P::Uni"
+	} else if a.LimitedPreemptive {
+		loggerName = "LP::Uni"
+	}
+	if a.Naive {
+		loggerName += "::Naive"
+	}
+	if a.Por {
+		loggerName += "::POR"
+	}
+	return loggerName
+}
+
+// timeModel returns the time model selected by the checked options.
+func (a analysis) timeModel() comm.TimeModel {
+	m := comm.TimeModel{Dense: a.DenseTime}
+	m.Resolution, _ = comm.ParseUnit(a.Resolution)
+	if a.OutputUnit != "" {
+		m.OutputUnit, _ = comm.ParseUnit(a.OutputUnit)
+	}
+	return m
+}
+
+// run analyses workload. It fails if the analysis does not support the jobs
+// of the workload.
+func (a analysis) run(workload comm.JobSet, logger *verbose.Logger) (*comm.AnalysisResult, error) {
+	for _, j := range workload {
+		if j.IsSelfSuspending() && (a.Cores > 1 || a.Por) {
+			return nil, errors.New("Self-suspending jobs are only supported by the uniprocessor analyses without partial-order reduction")
+		}
+	}
+
+	var iip comm.IIP
+	switch a.IIP {
+	case "p-rm":
+		iip = comm.NewPrecautiousRM(workload)
+	case "cw":
+		iip = comm.NewCriticalWindowEDF(workload)
+	default:
+		iip = comm.NullIIP{}
+	}
+
+	opts := comm.AnalysisOptions{
+		Timeout:   uint(a.Timeout),
+		MaxDepth:  uint(a.DepthLimit),
+		EarlyExit: !a.ContinueAfterMiss,
+		Naive:     a.Naive,
+		Cores:     uint(a.Cores),
+		IIP:       iip,
+		Logger:    logger,
+		NoGraph:   a.NoGraph,
+		Threads:   a.Threads,
+		TimeModel: a.timeModel(),
+
+		LimitedPreemptive: a.LimitedPreemptive,
+	}
+
+	if a.Preemptive {
+		return uni_preemptive.NewSpace(workload, opts).Explore(), nil
+	} else if a.Cores > 1 {
+		return global_non_preemptive.NewSpace(workload, opts).Explore(), nil
+	} else if a.Por {
+		return uni_non_preemptive_por.NewSpace(workload, opts).Explore(), nil
+	}
+	return uni_non_preemptive.NewSpace(workload, opts).Explore(), nil
+}
+
+// readWorkload reads the job set in inputFile, or expands the task set in
+// inputFile if tasks is set, and adds the precedence constraints of
+// precedenceFile. The format of inputFile is given by its extension unless
+// format is set. The times are read in the time model m.
+func readWorkload(inputFile, format string, tasks bool, horizon comm.Time, precedenceFile string, m comm.TimeModel, logger *verbose.Logger) (comm.JobSet, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(inputFile), ".")
+	}
+
+	var workload comm.JobSet
+	var err error
+	if tasks {
+		var taskSet comm.TaskSet
+		if format == "csv" {
+			taskSet, err = comm.ReadTaskSet(inputFile, m, logger)
+		} else if format == "yaml" {
+			taskSet, err = comm.ReadTaskSetYAML(inputFile, m, logger)
+		} else {
+			err = fmt.Errorf("%s: invalid format %q", inputFile, format)
+		}
+		if err == nil {
+			workload, err = taskSet.Expand(horizon, m)
+		}
 	} else {
-		commonLogger.Critical("Error: Invalid file extension")
+		if format == "csv" {
+			workload, err = comm.ReadJobSet(inputFile, m, logger)
+		} else if format == "yaml" {
+			workload, err = comm.ReadJobSetYAML(inputFile, m, logger)
+		} else if format == "json" {
+			workload, err = comm.ReadJobSetJSON(inputFile, m, logger)
+		} else {
+			err = fmt.Errorf("%s: invalid format %q", inputFile, format)
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	//read precedence file
 	precedenceFileExtension := filepath.Ext(precedenceFile)
 	if precedenceFile != "" {
 		if precedenceFileExtension == ".csv" {
-			comm.ReadPrecedence(precedenceFile, &workload, commonLogger)
+			err = comm.ReadPrecedence(precedenceFile, &workload, m, logger)
+		} else if precedenceFileExtension == ".yaml" {
+			err = comm.ReadPrecedenceYAML(precedenceFile, &workload, m, logger)
 		} else {
-			commonLogger.Critical("Error: Invalid file extension")
+			err = fmt.Errorf("%s: invalid file extension", precedenceFile)
+		}
+		if err != nil {
+			return nil, err
 		}
 	} else {
-		commonLogger.Warning("No precedence file provided")
+		logger.Warning("No precedence file provided")
 	}
-
-	if denseTime {
-		comm.WantDenseTimeModel()
+	if err := workload.Validate(m); err != nil {
+		return nil, err
 	}
+	return workload, nil
+}
 
-	if wantCsv {
-		csvOutputFile = strings.TrimSuffix(inputFile, filepath.Ext(inputFile)) + ".rta.csv"
-	}
-	dotOutputFile := strings.TrimSuffix(inputFile, filepath.Ext(inputFile))
-	start := time.Now()
-	if beNaive {
-		if por {
-			analysisLogger := verbose.New("NP::Uni::Naive::POR")
-			analysisLogger.AddHandler("1", sh)
-			uni_non_preemptive_por.ExploreNaively(workload, 10, true, 10, analysisLogger)
-			uni_non_preemptive_por.PrintResponseTimes()
-			uni_non_preemptive_por.MakeDotFile(dotOutputFile)
-			if wantCsv {
-				uni_non_preemptive_por.WriteResponseTimes(csvOutputFile)
+// batchResult is the outcome of the analysis of one job set in batch mode.
+type batchResult struct {
+	file   string
+	jobs   int
+	result *comm.AnalysisResult
+	err    error
+}
+
+// findJobSets returns the job-set files named by the batch inputs. An input
+// is a directory, whose job-set files are analysed, a glob pattern, a
+// manifest file listing one job-set file per line, or a job-set file.
+func findJobSets(inputs []string) ([]string, error) {
+	var files []string
+	for _, input := range inputs {
+		if info, err := os.Stat(input); err == nil && info.IsDir() {
+			entries, err := os.ReadDir(input)
+			if err != nil {
+				return nil, err
 			}
-		} else {
-			analysisLogger := verbose.New("NP::Uni::Naive")
-			analysisLogger.AddHandler("1", sh)
-			uni_non_preemptive.ExploreNaively(workload, 10, true, 10, analysisLogger)
-			uni_non_preemptive.PrintResponseTimes()
-			uni_non_preemptive.MakeDotFile(dotOutputFile)
-			if wantCsv {
-				uni_non_preemptive.WriteResponseTimes(csvOutputFile)
+			for _, entry := range entries {
+				if !entry.IsDir() && isJobSetFile(entry.Name()) {
+					files = append(files, filepath.Join(input, entry.Name()))
+				}
 			}
-		}
-	} else {
-		if por {
-			analysisLogger := verbose.New("NP::Uni::POR")
-			analysisLogger.AddHandler("1", sh)
-			uni_non_preemptive_por.Explore(workload, 10, true, 10, analysisLogger)
-			uni_non_preemptive_por.PrintResponseTimes()
-			uni_non_preemptive_por.MakeDotFile(dotOutputFile)
-			if wantCsv {
-				uni_non_preemptive_por.WriteResponseTimes(csvOutputFile)
+		} else if strings.ContainsAny(input, "*?[") {
+			matches, err := filepath.Glob(input)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", input, err)
+			}
+			for _, match := range matches {
+				if isJobSetFile(match) {
+					files = append(files, match)
+				}
 			}
+		} else if filepath.Ext(input) == ".txt" {
+			listed, err := readManifest(input)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, listed...)
 		} else {
-			analysisLogger := verbose.New("NP::Uni")
-			analysisLogger.AddHandler("1", sh)
-			uni_non_preemptive.Explore(workload, 10, true, 10, analysisLogger)
-			uni_non_preemptive.PrintResponseTimes()
-			uni_non_preemptive.MakeDotFile(dotOutputFile)
-			if wantCsv {
-				uni_non_preemptive.WriteResponseTimes(csvOutputFile)
+			files = append(files, input)
+		}
+	}
+	return files, nil
+}
+
+// readManifest returns the job-set files listed in a manifest, one per
+// line. Relative paths are relative to the manifest, and empty lines and
+// lines starting with '#' are skipped.
+func readManifest(manifest string) ([]string, error) {
+	f, err := os.Open(manifest)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var files []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(manifest), line)
+		}
+		files = append(files, line)
+	}
+	return files, scanner.Err()
+}
+
+// isJobSetFile reports whether file is a job set rather than a precedence
+// file or an output of a previous run.
+func isJobSetFile(file string) bool {
+	ext := filepath.Ext(file)
+	if ext != ".csv" && ext != ".yaml" && ext != ".json" {
+		return false
+	}
+	kind := filepath.Ext(strings.TrimSuffix(file, ext))
+	return kind != ".prec" && kind != ".rta"
+}
+
+// precedenceFileFor returns the precedence file stored next to a job-set
+// file, e.g., example4.prec.csv for example4.csv, or "" if there is none.
+func precedenceFileFor(file string) string {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+	for _, ext := range []string{".prec.csv", ".prec.yaml"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// runBatch analyses the job sets in files on a pool of workers, one per CPU
+// if workers is 0. The results are returned in the order of files.
+func runBatch(files []string, a analysis, workers int, commonLogger, logger *verbose.Logger) []batchResult {
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results := make([]batchResult, len(files))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = analyseFile(files[i], a, commonLogger, logger)
 			}
+		}()
+	}
+	for i := range files {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return results
+}
+
+func analyseFile(file string, a analysis, commonLogger, logger *verbose.Logger) batchResult {
+	workload, err := readWorkload(file, "", false, 0, precedenceFileFor(file), a.timeModel(), commonLogger)
+	if err == nil {
+		var result *comm.AnalysisResult
+		if result, err = a.run(workload, logger); err == nil {
+			return batchResult{file: file, jobs: len(workload), result: result}
 		}
 	}
+	commonLogger.Error("Error: ", err)
+	return batchResult{file: file, jobs: len(workload), err: err}
+}
 
-	fmt.Println("Exploration finished")
-	fmt.Println("Time elapsed: ", time.Since(start))
+// batchExitStatus returns the exit code of a batch: an invalid input comes
+// first, then a possible deadline miss, then an aborted exploration.
+func batchExitStatus(results []batchResult) int {
+	status := exitSchedulable
+	for _, r := range results {
+		if r.err != nil {
+			return exitInputError
+		}
+		switch exitStatus(r.result) {
+		case exitDeadlineMiss:
+			status = exitDeadlineMiss
+		case exitAborted:
+			if status == exitSchedulable {
+				status = exitAborted
+			}
+		}
+	}
+	return status
+}
 
+// writeSummary writes one csv row per job set to filename, or to the
+// standard output for "-". The largest response time and the smallest
+// slack are only given for schedulable, non-empty job sets.
+func writeSummary(filename string, results []batchResult) error {
+	var out io.Writer = os.Stdout
+	if filename != "-" {
+		f, err := os.Create(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"File", "Jobs", "Verdict", "States", "Edges", "Max WCRT", "Min slack", "Runtime"})
+	for _, r := range results {
+		if r.err != nil {
+			w.Write([]string{r.file, fmt.Sprint(r.jobs), "invalid input", "", "", "", "", ""})
+			continue
+		}
+
+		stats := r.result.Stats()
+		maxWCRT, minSlack := "", ""
+		if r.result.IsSchedulable() && r.jobs > 0 {
+			wcrt, slack := responseTimeBounds(r.result)
+			maxWCRT, minSlack = wcrt.OutputString(r.result.TimeModel), slack.OutputString(r.result.TimeModel)
+		}
+		w.Write([]string{
+			r.file,
+			fmt.Sprint(r.jobs),
+			r.result.Verdict(),
+			fmt.Sprint(stats.NumberOfStates),
+			fmt.Sprint(stats.NumberOfEdges),
+			maxWCRT,
+			minSlack,
+			strconv.FormatFloat(stats.CPUTime.Seconds(), 'f', -1, 64),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// responseTimeBounds returns the largest worst-case response time of the
+// explored jobs and the smallest difference between a deadline and a
+// worst-case completion time.
+func responseTimeBounds(r *comm.AnalysisResult) (comm.Time, comm.Time) {
+	maxWCRT := comm.Time(0)
+	minSlack := comm.Infinity()
+	for _, j := range r.Workload {
+		finishTime, ok := r.ResponseTimes[j.Name]
+		if !ok {
+			continue
+		}
+		wcct := finishTime.End
+		maxWCRT = comm.Maximum(maxWCRT, wcct-j.Arrival.Start)
+		minSlack = comm.Minimum(minSlack, j.Deadline-wcct)
+	}
+	return maxWCRT, minSlack
+}
+
+func printBatchReport(report io.Writer, results []batchResult) {
+	counts := make(map[string]int)
+	for _, r := range results {
+		if r.err != nil {
+			counts["invalid input"]++
+		} else {
+			counts[r.result.Verdict()]++
+		}
+	}
+	fmt.Fprintf(report, "Analysed %d job sets: %d schedulable, %d unschedulable, %d timed out, %d depth exceeded, %d invalid\n",
+		len(results), counts["schedulable"], counts["unschedulable"], counts["timed out"], counts["depth exceeded"], counts["invalid input"])
 }
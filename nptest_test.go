@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/lfkeitel/verbose"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestMain runs nptest instead of the tests if NPTEST_MAIN is set, so that
+// runNptest can check the outputs and the exit status of a run.
+func TestMain(m *testing.M) {
+	if os.Getenv("NPTEST_MAIN") != "" {
+		main()
+	}
+	os.Exit(m.Run())
+}
+
+// runNptest runs nptest with args in directory dir and returns its exit
+// status.
+func runNptest(t *testing.T, dir string, args ...string) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "NPTEST_MAIN=1")
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return 0
+}
+
+// copyExample copies the example file to directory dir.
+func copyExample(t *testing.T, dir, file string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("example", file))
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, file), data, 0644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestNoGraphOutput checks that no graph is written without the graph.
+func TestNoGraphOutput(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantDot bool
+	}{
+		{args: []string{"-j", "example.csv"}, wantDot: true},
+		{args: []string{"-j", "example.csv", "--no-graph"}, wantDot: false},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			dir := t.TempDir()
+			copyExample(t, dir, "example.csv")
+			if status := runNptest(t, dir, tt.args...); status != exitSchedulable {
+				t.Fatalf("exit status %d, want %d", status, exitSchedulable)
+			}
+			_, err := os.Stat(filepath.Join(dir, "example.dot"))
+			if gotDot := err == nil; gotDot != tt.wantDot {
+				t.Errorf("graph written: %v, want %v", gotDot, tt.wantDot)
+			}
+		})
+	}
+}
+
+const (
+	jobSetHeader = "Task ID,Job ID,Arrival min,Arrival max,Cost min,Cost max,Deadline,Priority\n"
+	// missJobSet is a job set whose only job misses its deadline.
+	missJobSet = jobSetHeader + "1,1,0,0,5,5,3,1\n"
+	// invalidJobSet is a job set with a malformed release.
+	invalidJobSet = jobSetHeader + "1,1,zero,0,5,5,3,1\n"
+)
+
+// batchInputs writes example.csv, miss.csv and invalid.csv to directory dir.
+func batchInputs(t *testing.T, dir string) {
+	t.Helper()
+	copyExample(t, dir, "example.csv")
+	for file, content := range map[string]string{"miss.csv": missJobSet, "invalid.csv": invalidJobSet} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestBatchSummary checks the summary row of a schedulable, an unschedulable
+// and an invalid job set.
+func TestBatchSummary(t *testing.T) {
+	dir := t.TempDir()
+	batchInputs(t, dir)
+	files := []string{filepath.Join(dir, "example.csv"), filepath.Join(dir, "miss.csv"), filepath.Join(dir, "invalid.csv")}
+	a := analysis{Name: "test", Cores: 1, IIP: "none", Resolution: "ns", Threads: 1}
+	logger := verbose.New("test")
+	results := runBatch(files, a, 2, logger, logger)
+
+	summary := filepath.Join(dir, "summary.csv")
+	if err := writeSummary(summary, results); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"File", "Jobs", "Verdict", "States", "Edges", "Max WCRT", "Min slack", "Runtime"},
+		{files[0], "12", "schedulable", "15", "16", "19", "1"},
+		{files[1], "1", "unschedulable", "2", "1", "", ""},
+		{files[2], "0", "invalid input", "", "", "", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("%d rows, want %d", len(rows), len(want))
+	}
+	if !reflect.DeepEqual(rows[0], want[0]) {
+		t.Errorf("header %v, want %v", rows[0], want[0])
+	}
+	for i, row := range rows[1:] {
+		if !reflect.DeepEqual(row[:len(row)-1], want[i+1]) {
+			t.Errorf("row %v, want %v", row[:len(row)-1], want[i+1])
+		}
+		// the runtime varies, but is only given for the analysed job sets
+		runtime := row[len(row)-1]
+		if results[i].err != nil {
+			if runtime != "" {
+				t.Errorf("%s: runtime %q of an invalid input", row[0], runtime)
+			}
+		} else if seconds, err := strconv.ParseFloat(runtime, 64); err != nil || seconds < 0 {
+			t.Errorf("%s: runtime %q", row[0], runtime)
+		}
+	}
+
+	if status := batchExitStatus(results); status != exitInputError {
+		t.Errorf("exit status %d, want %d", status, exitInputError)
+	}
+}
+
+// TestBatchExitStatus checks that an invalid input comes before a deadline
+// miss, which comes before an aborted exploration.
+func TestBatchExitStatus(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: []string{"batch", "example.csv"}, want: exitSchedulable},
+		{args: []string{"batch", "example.csv", "miss.csv"}, want: exitDeadlineMiss},
+		{args: []string{"batch", "example.csv", "-l", "2"}, want: exitAborted},
+		{args: []string{"batch", "example.csv", "miss.csv", "-l", "2"}, want: exitDeadlineMiss},
+		{args: []string{"batch", "example.csv", "miss.csv", "invalid.csv"}, want: exitInputError},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			dir := t.TempDir()
+			batchInputs(t, dir)
+			if status := runNptest(t, dir, tt.args...); status != tt.want {
+				t.Errorf("exit status %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
+
+// TestAbortedExitStatus checks that a run stopped at the depth limit or at
+// the timeout exits with exitAborted.
+func TestAbortedExitStatus(t *testing.T) {
+	tests := []struct {
+		args []string
+	}{
+		{args: []string{"-j", "example.csv", "-l", "2"}},
+		{args: []string{"-j", "example.csv", "-l", "2", "-p"}},
+		{args: []string{"-j", "example.csv", "-l", "2", "--preemptive"}},
+		{args: []string{"-j", "example.csv", "-l", "2", "-m", "2"}},
+		// the jobs of chain.csv run one after the other, which takes
+		// seconds to explore
+		{args: []string{"-j", "chain.csv", "-t", "1", "--no-graph"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			dir := t.TempDir()
+			copyExample(t, dir, "example.csv")
+			chain := []byte(jobSetHeader)
+			for i := 0; i < 2000; i++ {
+				chain = append(chain, fmt.Sprintf("1,%d,%d,%d,1,2,%d,%d\n", i+1, 10*i, 10*i, 10*i+5, i+1)...)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "chain.csv"), chain, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if status := runNptest(t, dir, tt.args...); status != exitAborted {
+				t.Errorf("exit status %d, want %d", status, exitAborted)
+			}
+		})
+	}
+}